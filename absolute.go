@@ -1,146 +1,210 @@
 package timeframe
 
 import (
-	"strconv"
 	"strings"
 	"time"
 )
 
 //Absolute parses a token like 2017-03-18 and returns the Range it represents
 func Absolute(s string, loc *time.Location) (Range, error) {
-	if len(s) < 4 || len(s) > 23 { //2017, 2017-03-18T22:50:00.000
-		return err() //cannot be a valid structure
-	}
-	if loc == nil {
-		loc = time.Local
-	}
-	i := strings.IndexByte(s, 0x2d /*-*/)
-	switch i {
-	case -1:
-		switch len(s) {
-		case 4: //2017
-			return parseYear(s, loc)
-		case 6: //201703
-			if allowYYYYMM {
-				return parseMonth(s[:4], s[4:], loc)
-			}
-		case 7: //2017W11, 2017077
-			if s[4] == 0x57 /*W*/ { //2017W11
-				return parseISOWeek(s[:4], s[5:], loc)
-			}
-			return parseOrdinalDate(s[:4], s[4:], loc)
-		case 8: //20170318, 2017W116
-			if s[4] == 0x57 /*W*/ { //2017W116
-				return parseISOWeekDate(s[:4], s[5:7], s[7:], loc)
-			}
-			return parseDate("20060102", s, loc)
-		case 11:
-			return parseTime("20060102T15", s, time.Hour, loc)
-		case 13:
-			return parseTime("20060102T1504", s, time.Minute, loc)
-		case 15:
-			return parseTime("20060102T150405", s, time.Second, loc)
-		case 19:
-			return parseTime("20060102T150405.000", s, time.Millisecond, loc)
+	return std.Absolute(s, loc)
+}
+
+//Absolute parses a token like 2017-03-18 and returns the Range it represents
+//
+//ISO 8601 time intervals like 2017-03-01/2017-03-18, 2017-03-01/P1W and P2D/2017-03-18 are accepted too,
+//in which each endpoint includes the whole of its own token
+//
+//The last of the hours, minutes and seconds may have a fraction of 1 to 9 digits after either . or , whose precision
+//sets the width of the Range, so 22:50:42.5 is a tenth of a second long and 22.5 six minutes from half past ten
+//
+//Times of day may end in a zone like Z, +05, +0530 (basic) or +05:30 (extended), which takes precedence over loc
+func (p *Parser) Absolute(s string, loc *time.Location) (Range, error) {
+	loc = p.location(loc)
+	if k := strings.IndexByte(s, 0x2f /*/*/); k != -1 {
+		x, e := p.interval(s, 0, k, loc)
+		return x.Range, e
+	}
+	sc := scanner{s: s}
+	return sc.finish(s, p.absolute(&sc, loc))
+}
+
+func (p *Parser) absolute(sc *scanner, loc *time.Location) Range {
+	fy := sc.skip(0x46 /*F*/) //FY2017, FY2017-Q2
+	if fy && !sc.skip(0x59 /*Y*/) {
+		sc.failAt(sc.i, ErrNotRecognised)
+	}
+	y := p.parseYear(sc)
+	if sc.end() { //2017
+		return p.months(fy, y, 0, 12, loc)
+	}
+	ext := sc.skip(0x2d /*-*/)
+	if sc.skip(0x51 /*Q*/) { //2017-Q1, 2017Q1
+		q := sc.num(1, 1, 4, ErrQuarterOutOfRange)
+		return p.months(fy, y, q*3-3, 3, loc)
+	}
+	if sc.skip(0x48 /*H*/) { //2017-H1, 2017H1
+		h := sc.num(1, 1, 2, ErrHalfOutOfRange)
+		return p.months(fy, y, h*6-6, 6, loc)
+	}
+	if fy {
+		sc.failAt(sc.i, ErrNotRecognised) //fiscal months, weeks and days are not supported
+		return Range{}
+	}
+	if sc.skip(0x57 /*W*/) { //2017-W11, 2017W11
+		w := sc.num(2, 1, weeksIn(y), ErrWeekOutOfRange)
+		if sc.end() {
+			return week(y, w, 0, 7, loc)
 		}
-	case 4:
-		switch len(s) {
-		case 7: //2017-03
-			return parseMonth(s[:4], s[5:], loc)
-		case 8: //2017-W11, 2017-077
-			if s[5] == 0x57 /*W*/ { //2017W116
-				return parseISOWeek(s[:4], s[6:], loc)
-			}
-			return parseOrdinalDate(s[:4], s[5:], loc)
-		case 10: //2017-03-18, 2017-W11-6
-			if s[5] == 0x57 /*W*/ && s[8] == 0x2d /*-*/ { //2017-W11-6
-				return parseISOWeekDate(s[:4], s[6:8], s[9:], loc)
+		sc.sep(ext, 0x2d /*-*/) //2017-W11-6, 2017W116
+		dw := sc.num(1, 1, 7, ErrDayOutOfRange)
+		return week(y, w, dw-1, 1, loc)
+	}
+	switch n := sc.digits(); {
+	case n == 3: //2017-077, 2017077
+		dy := sc.num(3, 1, daysIn(y, 0), ErrDayOutOfRange)
+		return day(y, 1, dy, 1, loc)
+	case n == 2 && ext, n == 4 && !ext, n == 2 && p.AllowYYYYMM: //2017-03, 20170318, 201703 (extension to ISO 8601)
+		m := sc.num(2, 1, 12, ErrMonthOutOfRange)
+		if n == 2 && sc.end() {
+			if p.Retail != nil {
+				return p.Retail.months(y*12+m-1, 1, loc)
 			}
-			return parseDate("2006-01-02", s, loc)
-		case 13:
-			return parseTime("2006-01-02T15", s, time.Hour, loc)
-		case 16:
-			return parseTime("2006-01-02T15:04", s, time.Minute, loc)
-		case 19:
-			return parseTime("2006-01-02T15:04:05", s, time.Second, loc)
-		case 23:
-			return parseTime("2006-01-02T15:04:05.000", s, time.Millisecond, loc)
+			return month(y, m, 1, loc)
 		}
+		sc.sep(ext, 0x2d /*-*/)
+		d := sc.num(2, 1, daysIn(y, m), ErrDayOutOfRange)
+		if sc.end() {
+			return day(y, m, d, 1, loc)
+		}
+		return p.clock(sc, ext, y, m, d, loc)
+	case n == 0:
+		sc.failAt(sc.i, ErrBadSeparator)
+	default:
+		sc.failAt(sc.i, ErrNotRecognised)
 	}
-	return err()
+	return Range{}
 }
 
-func parse(s string, min, max int) int {
-	i, e := strconv.Atoi(s)
-	if e != nil || i < min || i > max {
-		return -1
+//parseYear parses a four digit year, or when YearDigits is set a signed year of that many digits like +12017 or -0044
+func (p *Parser) parseYear(sc *scanner) int {
+	if p.YearDigits > 0 {
+		switch {
+		case sc.skip(0x2b /*+*/):
+			return sc.num(p.YearDigits, 0, pow10(p.YearDigits)-1, ErrYearOutOfRange)
+		case sc.skip(0x2d /*-*/):
+			return -sc.num(p.YearDigits, 0, pow10(p.YearDigits)-1, ErrYearOutOfRange)
+		}
 	}
-	return i
+	return sc.num(4, p.MinYear, p.MaxYear, ErrYearOutOfRange)
 }
 
-func parseYear(sy string, loc *time.Location) (Range, error) {
-	y := parse(sy, minYear, maxYear)
-	if y == -1 {
-		return err()
+//months returns the year, half or quarter of year y that is l months long and starts o months in, from the fiscal
+//calendar when fy is set, otherwise from the retail, fiscal or Gregorian calendar according to the Parser's options
+func (p *Parser) months(fy bool, y, o, l int, loc *time.Location) Range {
+	switch {
+	case fy:
+		return p.Fiscal.months(y, o, l, loc)
+	case p.Retail != nil:
+		return p.Retail.months(y*12+o, l, loc)
+	case p.UseFiscal:
+		return p.Fiscal.months(y, o, l, loc)
 	}
-	return year(y, 1, loc)
+	return month(y, o+1, l, loc)
 }
 
-func parseMonth(sy, sm string, loc *time.Location) (Range, error) {
-	y := parse(sy, minYear, maxYear)
-	m := parse(sm, 1, 12)
-	if y == -1 || m == -1 {
-		return err()
+//clock parses the time of day following a calendar date, like T22:50 or T2250, and any zone after it
+func (p *Parser) clock(sc *scanner, ext bool, y, m, d int, loc *time.Location) Range {
+	sc.expect(0x54 /*T*/)
+	hh := sc.num(2, 0, 23, ErrHourOutOfRange)
+	mm, ss, l := 0, 0, time.Hour
+	var o time.Duration //the fraction of the lowest-order component
+	switch {
+	case decimal(sc): //T22.5
+		o, l = fraction(sc, l, ErrHourOutOfRange)
+	case !zoned(sc):
+		sc.sep(ext, 0x3a /*:*/)
+		mm, l = sc.num(2, 0, 59, ErrMinuteOutOfRange), time.Minute
+		switch {
+		case decimal(sc): //T22:50.5
+			o, l = fraction(sc, l, ErrMinuteOutOfRange)
+		case !zoned(sc):
+			sc.sep(ext, 0x3a /*:*/)
+			ss, l = sc.num(2, 0, 59, ErrSecondOutOfRange), time.Second
+			if !zoned(sc) { //T22:50:42.5
+				if !decimal(sc) {
+					sc.failAt(sc.i, ErrBadSeparator)
+				}
+				o, l = fraction(sc, l, ErrSecondOutOfRange)
+			}
+		}
 	}
-	return month(y, m, 1, loc)
+	return instant(y, m, d, hh, mm, ss, o, l, zone(sc, ext, loc))
 }
 
-func parseISOWeek(sy, sw string, loc *time.Location) (Range, error) {
-	y := parse(sy, minYear, maxYear)
-	w := parse(sw, 1, 53)
-	if y == -1 || w == -1 {
-		return err()
+//decimal consumes the decimal sign, either . or , as per ISO 8601
+func decimal(sc *scanner) bool {
+	return sc.skip(0x2e /*.*/) || sc.skip(0x2c /*,*/)
+}
+
+//fraction consumes the 1 to 9 digits of a decimal fraction of unit, returning its length and that of the last digit
+func fraction(sc *scanner, unit time.Duration, reason error) (time.Duration, time.Duration) {
+	i := sc.i
+	f := sc.run(9, reason)
+	if sc.reason != nil {
+		return 0, unit
 	}
-	return week(y, w, 0, 7, loc)
+	u := unit / time.Duration(pow10(sc.i-i)) //exact, as an hour is a whole number of seconds
+	return u * time.Duration(f), u
 }
 
-func parseISOWeekDate(sy, sw, swd string, loc *time.Location) (Range, error) {
-	y := parse(sy, minYear, maxYear)
-	w := parse(sw, 1, 53)
-	dw := parse(swd, 1, 7)
-	if y == -1 || w == -1 || dw == -1 {
-		return err()
-	}
-	return week(y, w, dw-1, 1, loc)
+//zoned reports whether the time of day is complete, with nothing or only a zone left
+func zoned(sc *scanner) bool {
+	return sc.reason != nil || sc.i == len(sc.s) || strings.IndexByte("Z+-", sc.s[sc.i]) != -1
 }
 
-func parseOrdinalDate(sy, sdy string, loc *time.Location) (Range, error) {
-	y := parse(sy, minYear, maxYear)
-	dy := parse(sdy, 1, 366)
-	if y == -1 || dy == -1 {
-		return err()
+//zone parses a zone like Z, +05, +0530 or +05:30, returning loc if there is none
+//
+//As elsewhere, the minutes are separated by a colon in the extended format and not in the basic one
+//
+//The zone takes precedence over loc, so 2017-03-18T22:50+05:30 is the same instant whatever loc is
+func zone(sc *scanner, ext bool, loc *time.Location) *time.Location {
+	if sc.skip(0x5a /*Z*/) {
+		return time.UTC
+	}
+	sign := 1
+	switch {
+	case sc.skip(0x2b /*+*/):
+	case sc.skip(0x2d /*-*/):
+		sign = -1
+	default:
+		return loc
+	}
+	hh := sc.num(2, 0, 23, ErrZoneOutOfRange)
+	mm := 0
+	if sc.i < len(sc.s) {
+		sc.sep(ext, 0x3a /*:*/)
+		mm = sc.num(2, 0, 59, ErrZoneOutOfRange)
 	}
-	return day(y, 1, dy, 1, loc)
+	if sc.reason != nil {
+		return loc
+	}
+	if hh == 0 && mm == 0 {
+		return time.UTC
+	}
+	return time.FixedZone("", sign*(hh*3600+mm*60))
 }
 
-func parseDate(layout, value string, loc *time.Location) (Range, error) {
-	t, e := time.ParseInLocation(layout, value, loc)
-	if e != nil {
-		return err()
-	}
-	return Range{
-		LowerInc: t,
-		UpperExc: t.AddDate(0, 0, 1),
-	}, nil
+//daysIn returns the number of days in month m of year y, or in the whole year when m is 0
+func daysIn(y, m int) int {
+	if m == 0 {
+		return time.Date(y, 12, 31, 0, 0, 0, 0, time.UTC).YearDay()
+	}
+	return time.Date(y, time.Month(m)+1, 0, 0, 0, 0, 0, time.UTC).Day()
 }
 
-func parseTime(layout, value string, d time.Duration, loc *time.Location) (Range, error) {
-	t, e := time.ParseInLocation(layout, value, loc)
-	if e != nil {
-		return err()
-	}
-	return Range{
-		LowerInc: t,
-		UpperExc: t.Add(d),
-	}, nil
+//weeksIn returns the number of ISO weeks in year y
+func weeksIn(y int) int {
+	_, w := time.Date(y, 12, 28, 0, 0, 0, 0, time.UTC).ISOWeek()
+	return w
 }
@@ -1,6 +1,7 @@
 package timeframe
 
 import (
+	"errors"
 	"testing"
 	"time"
 )
@@ -36,10 +37,15 @@ func TestBadAbsolute(t *testing.T) {
 		"2017-03-18x22", "2017-03-18x22:50", "2017-03-18T2", "2017-03-18T22:5",
 		"2017-03-18T22x50", "2017-03-18T22:50x42", "2017-03-18T22:50:42x000",
 		"20170318T225042x000", "20170318T225042000",
-		"2017-03-18T22:50:42.0", "2017-03-18T22:50:42.00", "2017-03-18T22:50:42.0000",
-
-		//TODO: tests for a year that has only 52 weeks in it?
-		//TODO: tests for a year that has only 365 days in it?
+		"2017-03-18T22:50:42.", "2017-03-18T22:50:42,", "2017-03-18T22:50:42.0123456789", "2017-03-18T22:50:42.,5",
+		"2017-03-18T22:50:42.5.5", "2017-03-18T22.5:50", "2017-03-18T22:50.5:42", "2017-03-18T22.0123456789", "2017-03-18T22:50:42:5", "2017-03-18T22:50:42.5x",
+		"2017+03", "2017W+1", "2017-+77", //signs are not digits
+		"2017Q0", "2017-Q5", "2017Q", "2017-Q01", "2017-Q1-1", "2017H0", "2017-H3", "2017-H", "2017H12",
+		"2017-03-18Z", "2017-03-18T22:50z", "2017-03-18T22:50ZZ", "2017-03-18T22:50Z+01", //zones
+		"2017-03-18T22:50+5", "2017-03-18T22:50+053", "2017-03-18T22:50+05:3", "2017-03-18T22:50+05:",
+		"2017-03-18T22:50+24", "2017-03-18T22:50+05:60", "2017-03-18T22:50+", "2017-03-18T22:50-",
+		"2017-03-18T22:50+05:30:00", "2017-03-18T22:+05",
+		"20170318T2250+05:30", "2017-03-18T22:50+0530", //the zone's colon follows the format
 	}
 	for _, p := range patterns {
 		t.Run(p, func(t *testing.T) {
@@ -51,6 +57,35 @@ func TestBadAbsolute(t *testing.T) {
 	}
 }
 
+//TestAbsoluteCalendarLimits verifies weeks and days beyond the end of their year or month are rejected,
+//where they used to roll over into the next, so 2017-W53 was 2018-W01 and 2017-366 was 2018-01-01
+func TestAbsoluteCalendarLimits(t *testing.T) {
+	testCases := []struct {
+		pat    string
+		reason error
+	}{
+		{"2017W53", ErrWeekOutOfRange}, //2017 has only 52 weeks
+		{"2017-W53", ErrWeekOutOfRange},
+		{"2017W531", ErrWeekOutOfRange},
+		{"2017-W53-1", ErrWeekOutOfRange},
+		{"2017366", ErrDayOutOfRange}, //2017 has only 365 days
+		{"2017-366", ErrDayOutOfRange},
+		{"20170229", ErrDayOutOfRange},
+		{"2017-02-29", ErrDayOutOfRange},
+		{"2017-04-31", ErrDayOutOfRange},
+		{"2017-02-30", ErrDayOutOfRange},
+		{"2016-02-30", ErrDayOutOfRange},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pat, func(t *testing.T) {
+			r, err := Absolute(tc.pat, nil)
+			if !r.IsZero() || !errors.Is(err, tc.reason) {
+				t.Error(r, err)
+			}
+		})
+	}
+}
+
 //TestAbsoluteLocation verifies the returned Range maintains the provided location
 func TestAbsoluteLocation(t *testing.T) {
 	ny, _ := time.LoadLocation("America/New_York")
@@ -89,6 +124,14 @@ func TestAbsolute(t *testing.T) {
 		{"2017W116", "2017-03-18", "2017-03-19"},
 		{"2017-077", "2017-03-18", "2017-03-19"},
 		{"2017077", "2017-03-18", "2017-03-19"},
+		{"2015-W53", "2015-12-28", "2016-01-04"},
+		{"2016-366", "2016-12-31", "2017-01-01"},
+		{"2016-02-29", "2016-02-29", "2016-03-01"},
+		{"2017-Q1", "2017-01-01", "2017-04-01"},
+		{"2017Q3", "2017-07-01", "2017-10-01"},
+		{"2017-Q4", "2017-10-01", "2018-01-01"},
+		{"2017-H1", "2017-01-01", "2017-07-01"},
+		{"2017H2", "2017-07-01", "2018-01-01"},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.pat, func(t *testing.T) {
@@ -118,6 +161,16 @@ func TestAbsoluteTime2(t *testing.T) {
 		{"2017-03-18T22:51:42.123", "2017-03-18 22:51:42.123", "2017-03-18 22:51:42.124"},
 		{"20170318T225142", "2017-03-18 22:51:42.000", "2017-03-18 22:51:43.000"},
 		{"20170318T225142.123", "2017-03-18 22:51:42.123", "2017-03-18 22:51:42.124"},
+		{"2017-03-18T22:51:42.5", "2017-03-18 22:51:42.500", "2017-03-18 22:51:42.600"},
+		{"2017-03-18T22:51:42,5", "2017-03-18 22:51:42.500", "2017-03-18 22:51:42.600"},
+		{"2017-03-18T22:51:42.12", "2017-03-18 22:51:42.120", "2017-03-18 22:51:42.130"},
+		{"20170318T225142,999", "2017-03-18 22:51:42.999", "2017-03-18 22:51:43.000"},
+		{"2017-03-18T22.5", "2017-03-18 22:30:00.000", "2017-03-18 22:36:00.000"},
+		{"2017-03-18T22,25", "2017-03-18 22:15:00.000", "2017-03-18 22:15:36.000"},
+		{"20170318T22.999", "2017-03-18 22:59:56.400", "2017-03-18 23:00:00.000"},
+		{"2017-03-18T22:50.5", "2017-03-18 22:50:30.000", "2017-03-18 22:50:36.000"},
+		{"20170318T2250,25", "2017-03-18 22:50:15.000", "2017-03-18 22:50:15.600"},
+		{"2017-03-18T22:50.0001", "2017-03-18 22:50:00.006", "2017-03-18 22:50:00.012"},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.pat, func(t *testing.T) {
@@ -135,6 +188,35 @@ func TestAbsoluteTime2(t *testing.T) {
 	}
 }
 
+func TestAbsoluteFraction(t *testing.T) {
+	loc := time.Local
+	testCases := []struct {
+		pat   string
+		ns    int
+		width time.Duration
+	}{
+		{"2017-03-18T22:51:42.0", 0, 100 * time.Millisecond},
+		{"2017-03-18T22:51:42.00", 0, 10 * time.Millisecond},
+		{"2017-03-18T22:51:42.0000", 0, 100 * time.Microsecond},
+		{"2017-03-18T22:51:42.1234", 123400000, 100 * time.Microsecond},
+		{"2017-03-18T22:51:42,123456", 123456000, time.Microsecond},
+		{"2017-03-18T22:51:42.123456789", 123456789, time.Nanosecond},
+		{"20170318T225142.000000001", 1, time.Nanosecond},
+		{"2017-03-18T22:51:42.999999999Z", 999999999, time.Nanosecond},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pat, func(t *testing.T) {
+			r, err := Absolute(tc.pat, loc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.LowerInc.Second() != 42 || r.LowerInc.Nanosecond() != tc.ns || r.Duration() != tc.width {
+				t.Error(r)
+			}
+		})
+	}
+}
+
 func TestAbsoluteTime(t *testing.T) {
 	const format = "2006-01-02 15:04"
 	loc := time.Local
@@ -164,6 +246,93 @@ func TestAbsoluteTime(t *testing.T) {
 	}
 }
 
+func TestAbsoluteExpandedYear(t *testing.T) {
+	loc := time.UTC
+	five, four := NewParser(), NewParser()
+	five.YearDigits, four.YearDigits = 5, 4
+	testCases := []struct {
+		p     *Parser
+		pat   string
+		lower time.Time
+		upper time.Time
+	}{
+		{five, "+12017", time.Date(12017, 1, 1, 0, 0, 0, 0, loc), time.Date(12018, 1, 1, 0, 0, 0, 0, loc)},
+		{five, "+12017-03-18", time.Date(12017, 3, 18, 0, 0, 0, 0, loc), time.Date(12017, 3, 19, 0, 0, 0, 0, loc)},
+		{five, "+120170318", time.Date(12017, 3, 18, 0, 0, 0, 0, loc), time.Date(12017, 3, 19, 0, 0, 0, 0, loc)},
+		{five, "+12017-03", time.Date(12017, 3, 1, 0, 0, 0, 0, loc), time.Date(12017, 4, 1, 0, 0, 0, 0, loc)},
+		{five, "+12017-077", time.Date(12017, 3, 18, 0, 0, 0, 0, loc), time.Date(12017, 3, 19, 0, 0, 0, 0, loc)},
+		{five, "+12017-Q2", time.Date(12017, 4, 1, 0, 0, 0, 0, loc), time.Date(12017, 7, 1, 0, 0, 0, 0, loc)},
+		{five, "+12017-03-18T22:50Z", time.Date(12017, 3, 18, 22, 50, 0, 0, loc), time.Date(12017, 3, 18, 22, 51, 0, 0, loc)},
+		{five, "-00044-03-15", time.Date(-44, 3, 15, 0, 0, 0, 0, loc), time.Date(-44, 3, 16, 0, 0, 0, 0, loc)},
+		{five, "2017", time.Date(2017, 1, 1, 0, 0, 0, 0, loc), time.Date(2018, 1, 1, 0, 0, 0, 0, loc)}, //unsigned years are unaffected
+		{four, "-0044-03-15", time.Date(-44, 3, 15, 0, 0, 0, 0, loc), time.Date(-44, 3, 16, 0, 0, 0, 0, loc)},
+		{four, "-0044-W11", time.Date(-44, 3, 12, 0, 0, 0, 0, loc), time.Date(-44, 3, 19, 0, 0, 0, 0, loc)},
+		{four, "-0044W117", time.Date(-44, 3, 18, 0, 0, 0, 0, loc), time.Date(-44, 3, 19, 0, 0, 0, 0, loc)},
+		{four, "-0044-075", time.Date(-44, 3, 15, 0, 0, 0, 0, loc), time.Date(-44, 3, 16, 0, 0, 0, 0, loc)}, //a leap year
+		{four, "+0000-12", time.Date(0, 12, 1, 0, 0, 0, 0, loc), time.Date(1, 1, 1, 0, 0, 0, 0, loc)},
+		{four, "-0001/+0001", time.Date(-1, 1, 1, 0, 0, 0, 0, loc), time.Date(2, 1, 1, 0, 0, 0, 0, loc)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pat, func(t *testing.T) {
+			r, err := tc.p.Absolute(tc.pat, loc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.lower != r.LowerInc || tc.upper != r.UpperExc {
+				t.Error(r)
+			}
+		})
+	}
+	for _, pat := range []string{"+2017-03-18", "+120173", "-44-03-15", "+-12017", "+12017-13"} {
+		if _, err := five.Absolute(pat, loc); err == nil {
+			t.Error(pat)
+		}
+	}
+	if _, err := Absolute("+12017-03-18", loc); err == nil {
+		t.Error("opt-in")
+	}
+	if r, err := five.Expand("-00044-03-15+1y", loc); err != nil || r.LowerInc != time.Date(-43, 3, 15, 0, 0, 0, 0, loc) {
+		t.Error(r, err)
+	}
+}
+
+func TestAbsoluteZone(t *testing.T) {
+	const format = "2006-01-02 15:04:05.000"
+	loc, _ := time.LoadLocation("Europe/London") //overridden by the zone
+	testCases := []struct {
+		pat   string
+		lower string //in UTC
+		upper string
+	}{
+		{"2017-03-18T22Z", "2017-03-18 22:00:00.000", "2017-03-18 23:00:00.000"},
+		{"2017-03-18T22:50Z", "2017-03-18 22:50:00.000", "2017-03-18 22:51:00.000"},
+		{"2017-03-18T22:50:42Z", "2017-03-18 22:50:42.000", "2017-03-18 22:50:43.000"},
+		{"2017-03-18T22:50:42.123Z", "2017-03-18 22:50:42.123", "2017-03-18 22:50:42.124"},
+		{"2017-03-18T22:50+05:30", "2017-03-18 17:20:00.000", "2017-03-18 17:21:00.000"},
+		{"20170318T2250+0530", "2017-03-18 17:20:00.000", "2017-03-18 17:21:00.000"},
+		{"2017-03-18T22:50-05", "2017-03-19 03:50:00.000", "2017-03-19 03:51:00.000"},
+		{"2017-03-18T22:50+00:00", "2017-03-18 22:50:00.000", "2017-03-18 22:51:00.000"},
+		{"20170318T2250-0130", "2017-03-19 00:20:00.000", "2017-03-19 00:21:00.000"},
+		{"20170318T22+01", "2017-03-18 21:00:00.000", "2017-03-18 22:00:00.000"},
+		{"2017-06-18T22:50Z", "2017-06-18 22:50:00.000", "2017-06-18 22:51:00.000"}, //not BST
+		{"2017-03-18T22:00Z/23:00Z", "2017-03-18 22:00:00.000", "2017-03-18 23:01:00.000"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pat, func(t *testing.T) {
+			r, err := Absolute(tc.pat, loc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if inc, _ := time.Parse(format, tc.lower); !inc.Equal(r.LowerInc) {
+				t.Errorf("L %s %s", inc, r.LowerInc)
+			}
+			if exc, _ := time.Parse(format, tc.upper); !exc.Equal(r.UpperExc) {
+				t.Errorf("U %s %s", exc, r.UpperExc)
+			}
+		})
+	}
+}
+
 func TestAbsoluteDaylightSavingTime(t *testing.T) {
 	const format = "2006-01-02 15:04 MST"
 	loc, _ := time.LoadLocation("Europe/London")
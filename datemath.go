@@ -0,0 +1,159 @@
+package timeframe
+
+import (
+	"strings"
+	"time"
+)
+
+//DateMath evaluates an expression like now-7d/d against t, as per Elasticsearch and Grafana
+func DateMath(s string, t *time.Time) (time.Time, error) {
+	return std.DateMath(s, t)
+}
+
+//DateMath evaluates an expression like now-7d/d against t, as per Elasticsearch and Grafana
+//
+//The anchor is either now, meaning t, or a token like 2017-03-18|| meaning the start of that token in t's location.
+//It is followed by any number of additions like +1d, subtractions like -7d and roundings like /d, applied in turn,
+//where the units are y M w d h H m s for years, months, weeks, days, hours (either), minutes and seconds.
+//Roundings go down to the start of the Gregorian unit, even when the Parser has a Retail or UseFiscal calendar,
+//with weeks starting on the Parser's FirstWeekday
+//
+//A nil t is taken to mean the Parser's Clock, in the Parser's default location
+func (p *Parser) DateMath(s string, t *time.Time) (time.Time, error) {
+	if t == nil {
+		now := p.now().In(p.location(nil))
+		t = &now
+	}
+	return p.dateMath(s, 0, len(s), false, t)
+}
+
+//DateMathRange evaluates an expression like now-7d/d..now/d against t and returns the Range between its two sides
+func DateMathRange(s string, t *time.Time) (Range, error) {
+	return std.DateMathRange(s, t)
+}
+
+//DateMathRange evaluates an expression like now-7d/d..now/d against t and returns the Range between its two sides
+//
+//As per Elasticsearch the roundings of the upper side go up, so now-7d/d..now/d includes all of today,
+//and a single expression like now/w stands for both sides, giving the whole of this week
+func (p *Parser) DateMathRange(s string, t *time.Time) (Range, error) {
+	if t == nil {
+		now := p.now().In(p.location(nil))
+		t = &now
+	}
+	i, j := 0, len(s)                         //the upper side starts at i, the lower ends at j
+	if k := strings.Index(s, ".."); k != -1 { //now-7d/d..now/d
+		i, j = k+2, k
+	}
+	lower, e := p.dateMath(s, 0, j, false, t)
+	if e != nil {
+		return Range{}, e
+	}
+	upper, e := p.dateMath(s, i, len(s), true, t)
+	if e != nil {
+		return Range{}, e
+	}
+	if !lower.Before(upper) {
+		return fail(DateMathToken, s, i, ErrInvertedRange)
+	}
+	return Range{
+		LowerInc: lower,
+		UpperExc: upper,
+	}, nil
+}
+
+//dateMath evaluates the expression s[i:j], rounding up to the start of the next unit when up is set
+func (p *Parser) dateMath(s string, i, j int, up bool, t *time.Time) (time.Time, error) {
+	g := *p //Elasticsearch rounds to Gregorian units, whatever calendar the Parser's relative tokens use
+	g.Retail, g.UseFiscal = nil, false
+	a := *t
+	sc := scanner{s: s[:j], i: i}
+	if strings.HasPrefix(sc.s[i:], "now") {
+		sc.i += 3
+	} else if k := strings.Index(sc.s[i:], "||"); k != -1 { //2017-03-18||
+		r, e := g.endpoint(s, i, i+k, t.Location())
+		if e != nil {
+			return time.Time{}, e
+		}
+		a, sc.i = r.LowerInc, i+k+2
+	} else {
+		sc.failAt(i, ErrNotRecognised)
+	}
+	for sc.reason == nil && sc.i < len(sc.s) {
+		switch {
+		case sc.skip(0x2f /*/*/): //now/d
+			if dp, ok := mathUnit(&sc); ok {
+				r, _ := g.newRange(dp, 0, 0, &a)
+				if a = r.LowerInc; up {
+					a = r.UpperExc
+				}
+			}
+		case sc.skip(0x2b /*+*/): //now+1d
+			n := mathNum(&sc)
+			if dp, ok := mathUnit(&sc); ok {
+				a = mathAdd(a, dp, n)
+			}
+		case sc.skip(0x2d /*-*/): //now-1d
+			n := mathNum(&sc)
+			if dp, ok := mathUnit(&sc); ok {
+				a = mathAdd(a, dp, -n)
+			}
+		default:
+			sc.failAt(sc.i, ErrNotRecognised)
+		}
+	}
+	if sc.reason != nil {
+		return time.Time{}, &ParseError{
+			Input:  s,
+			Offset: sc.fail,
+			Kind:   DateMathToken,
+			Reason: sc.reason,
+		}
+	}
+	return a, nil
+}
+
+//mathUnits are the date math units, named as per relative tokens
+var mathUnits = [...]string{"year", "month", "week", "day", "hour", "hour", "minute", "second"}
+
+//mathUnit consumes one of the date math units yMwdhHms, failing with ErrUnknownUnit if something else is next
+func mathUnit(sc *scanner) (string, bool) {
+	if sc.reason != nil {
+		return "", false
+	}
+	if sc.i < len(sc.s) {
+		if k := strings.IndexByte("yMwdhHms", sc.s[sc.i]); k != -1 {
+			sc.i++
+			return mathUnits[k], true
+		}
+	}
+	sc.failAt(sc.i, ErrUnknownUnit)
+	return "", false
+}
+
+//mathNum consumes the amount of an addition or subtraction, which defaults to 1 as per Elasticsearch
+func mathNum(sc *scanner) int {
+	if sc.digits() == 0 {
+		return 1
+	}
+	return sc.run(6, ErrOffsetOutOfRange)
+}
+
+//mathAdd adds n of unit dp to t, calendar units through AddDate so that now-1d is the same time yesterday
+func mathAdd(t time.Time, dp string, n int) time.Time {
+	switch dp {
+	case "year":
+		return t.AddDate(n, 0, 0)
+	case "month":
+		return t.AddDate(0, n, 0)
+	case "week":
+		return t.AddDate(0, 0, n*7)
+	case "day":
+		return t.AddDate(0, 0, n)
+	case "hour":
+		return t.Add(time.Duration(n) * time.Hour)
+	case "minute":
+		return t.Add(time.Duration(n) * time.Minute)
+	}
+	return t.Add(time.Duration(n) * time.Second)
+}
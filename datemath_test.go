@@ -0,0 +1,151 @@
+package timeframe
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDateMath(t *testing.T) {
+	const format = "2006-01-02 15:04:05"
+	loc, _ := time.LoadLocation("Europe/London")
+	rel := time.Date(2017, 03, 15, 22, 50, 30, 0, loc) //a Wednesday
+	testCases := []struct {
+		pat string
+		exp string
+	}{
+		{"now", "2017-03-15 22:50:30"},
+		{"now-7d", "2017-03-08 22:50:30"},
+		{"now+d", "2017-03-16 22:50:30"},
+		{"now-2w", "2017-03-01 22:50:30"},
+		{"now-7d/d", "2017-03-08 00:00:00"},
+		{"now/w", "2017-03-13 00:00:00"},
+		{"now/y", "2017-01-01 00:00:00"},
+		{"now-1M/M+1d", "2017-02-02 00:00:00"},
+		{"now-1h/H", "2017-03-15 21:00:00"},
+		{"now/h-30m", "2017-03-15 21:30:00"},
+		{"now/m", "2017-03-15 22:50:00"},
+		{"now+90s", "2017-03-15 22:52:00"},
+		{"now-19d+2h", "2017-02-25 00:50:30"},
+		{"now-18d/d+36h", "2017-02-26 12:00:00"},
+		{"now-11d/d-1h", "2017-03-03 23:00:00"},
+		{"now-4d-18h", "2017-03-11 04:50:30"},
+		{"2017-03-18||", "2017-03-18 00:00:00"},
+		{"2017-03-18||+1M/d", "2017-04-18 00:00:00"},
+		{"2017-03||/y", "2017-01-01 00:00:00"},
+		{"2017-03-25T12||+1d", "2017-03-26 12:00:00"}, //clocks go forward on the 26th
+		{"2017-03-25T12||+24h", "2017-03-26 13:00:00"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pat, func(t *testing.T) {
+			r, err := DateMath(tc.pat, &rel)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if exp, _ := time.ParseInLocation(format, tc.exp, loc); exp != r {
+				t.Error(r)
+			}
+		})
+	}
+}
+
+func TestDateMathGregorian(t *testing.T) {
+	loc := time.UTC
+	rel := time.Date(2017, 03, 18, 22, 50, 30, 0, loc)
+	nrf, fiscal := NewParser(), NewParser()
+	nrf.Retail = NewNRFCalendar()
+	fiscal.Fiscal, fiscal.UseFiscal = FiscalYear{Month: time.April, Day: 1}, true
+	testCases := []struct {
+		pat string
+		exp time.Time
+	}{
+		{"now/M", time.Date(2017, 3, 1, 0, 0, 0, 0, loc)},
+		{"now/y", time.Date(2017, 1, 1, 0, 0, 0, 0, loc)},
+		{"2017-03||/M", time.Date(2017, 3, 1, 0, 0, 0, 0, loc)},
+	}
+	for _, p := range []*Parser{nrf, fiscal} {
+		for _, tc := range testCases {
+			t.Run(tc.pat, func(t *testing.T) {
+				r, err := p.DateMath(tc.pat, &rel)
+				if err != nil || r != tc.exp {
+					t.Error(r, err)
+				}
+			})
+		}
+	}
+	if r, err := nrf.DateMathRange("now/y", &rel); err != nil || r.UpperExc != time.Date(2018, 1, 1, 0, 0, 0, 0, loc) {
+		t.Error(r, err)
+	}
+}
+
+func TestDateMathRange(t *testing.T) {
+	const format = "2006-01-02 15:04:05"
+	loc, _ := time.LoadLocation("Europe/London")
+	rel := time.Date(2017, 03, 15, 22, 50, 30, 0, loc) //a Wednesday
+	testCases := []struct {
+		pat   string
+		lower string
+		upper string
+	}{
+		{"now-7d/d..now/d", "2017-03-08 00:00:00", "2017-03-16 00:00:00"},
+		{"now-1h..now", "2017-03-15 21:50:30", "2017-03-15 22:50:30"},
+		{"now/w", "2017-03-13 00:00:00", "2017-03-20 00:00:00"},
+		{"now-1M/M", "2017-02-01 00:00:00", "2017-03-01 00:00:00"},
+		{"now-1M/M..now-1M/M", "2017-02-01 00:00:00", "2017-03-01 00:00:00"},
+		{"now-1M/M+1d", "2017-02-02 00:00:00", "2017-03-02 00:00:00"}, //rounds up before adding
+		{"2017-03-01||..2017-03-18||/d", "2017-03-01 00:00:00", "2017-03-19 00:00:00"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pat, func(t *testing.T) {
+			r, err := DateMathRange(tc.pat, &rel)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if inc, _ := time.ParseInLocation(format, tc.lower, loc); inc != r.LowerInc {
+				t.Errorf("L %s %s", inc, r.LowerInc)
+			}
+			if exc, _ := time.ParseInLocation(format, tc.upper, loc); exc != r.UpperExc {
+				t.Errorf("U %s %s", exc, r.UpperExc)
+			}
+		})
+	}
+}
+
+func TestBadDateMath(t *testing.T) {
+	rel := time.Date(2017, 03, 15, 22, 50, 30, 0, time.UTC)
+	testCases := []struct {
+		pat    string
+		offset int
+		reason error
+	}{
+		{"", 0, ErrNotRecognised},
+		{"then", 0, ErrNotRecognised},
+		{"nowd", 3, ErrNotRecognised},
+		{"now-", 4, ErrUnknownUnit},
+		{"now-1", 5, ErrUnknownUnit},
+		{"now-1x", 5, ErrUnknownUnit},
+		{"now/", 4, ErrUnknownUnit},
+		{"now/D", 4, ErrUnknownUnit},
+		{"now-1234567d", 4, ErrOffsetOutOfRange},
+		{"now-1d now", 6, ErrNotRecognised},
+		{"2017-03-18", 0, ErrNotRecognised},
+		{"2017-13-18||", 5, ErrMonthOutOfRange},
+		{"||+1d", 0, ErrNotRecognised},
+		{"now..now-1d", 5, ErrInvertedRange},
+		{"now/d..", 7, ErrNotRecognised},
+		{"..now", 0, ErrNotRecognised},
+		{"now..now..now", 8, ErrNotRecognised},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pat, func(t *testing.T) {
+			r, err := DateMathRange(tc.pat, &rel)
+			var pe *ParseError
+			if !r.IsZero() || !errors.As(err, &pe) {
+				t.Fatal(r, err)
+			}
+			if pe.Input != tc.pat || pe.Offset != tc.offset || pe.Reason != tc.reason {
+				t.Errorf("%q %d %v", pe.Input, pe.Offset, pe.Reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package timeframe
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+//Duration represents an ISO 8601 duration like P1Y2M3DT4H5M6.5S
+//
+//Unlike time.Duration the calendar components have no fixed length, a month may be 28 to 31 days
+//and a day 23 to 25 hours, so a Duration only becomes a span of time once it is applied to a time.Time
+type Duration struct {
+	Years, Months, Weeks, Days int
+	Hours, Minutes, Seconds    int
+	Nanoseconds                int //fraction of a second, 0-999999999, though String carries any more into Seconds
+}
+
+//ParseDuration parses an ISO 8601 duration like P1Y2M3DT4H5M6.5S or P2W
+//
+//Components must appear in order but may each be omitted, and only seconds may have a fraction
+func ParseDuration(s string) (Duration, error) {
+	sc := scanner{s: s}
+	d := parseDuration(&sc)
+	if sc.reason != nil {
+		return Duration{}, &ParseError{
+			Input:  s,
+			Offset: sc.fail,
+			Kind:   DurationToken,
+			Reason: sc.reason,
+		}
+	}
+	return d, nil
+}
+
+func parseDuration(sc *scanner) Duration {
+	var d Duration
+	if !sc.skip(0x50 /*P*/) {
+		sc.failAt(sc.i, ErrNotRecognised)
+		return d
+	}
+	units, fields := "YMWD", []*int{&d.Years, &d.Months, &d.Weeks, &d.Days}
+	clock, seen := false, false //seen is whether the latest designator, P or T, has been followed by a component
+	for sc.reason == nil && sc.i < len(sc.s) {
+		if !clock && sc.skip(0x54 /*T*/) {
+			units, fields, clock, seen = "HMS", []*int{&d.Hours, &d.Minutes, &d.Seconds}, true, false
+			continue
+		}
+		v := sc.run(6, ErrNotRecognised)
+		if clock && (sc.skip(0x2e /*.*/) || sc.skip(0x2c /*,*/)) { //6.5S, 6,5S
+			i := sc.i
+			f := sc.run(9, ErrNotRecognised)
+			d.Nanoseconds = f * pow10(9-(sc.i-i))
+			if sc.i < len(sc.s) && sc.s[sc.i] != 0x53 /*S*/ {
+				sc.failAt(sc.i, ErrUnknownUnit) //only seconds may have a fraction
+			}
+		}
+		if sc.reason != nil {
+			break
+		}
+		k := -1
+		if sc.i < len(sc.s) {
+			k = strings.IndexByte(units, sc.s[sc.i])
+		}
+		if k == -1 {
+			sc.failAt(sc.i, ErrUnknownUnit) //also catches units given out of order
+			break
+		}
+		*fields[k] = v
+		units, fields, seen = units[k+1:], fields[k+1:], true
+		sc.i++
+	}
+	if !seen {
+		sc.failAt(sc.i, ErrNotRecognised) //P and T must each be followed by a component
+	}
+	return d
+}
+
+//AddTo returns t plus d, applying the calendar components through AddDate and then the clock components through Add
+func (d Duration) AddTo(t time.Time) time.Time {
+	return d.addTo(t, +1)
+}
+
+func (d Duration) addTo(t time.Time, sign int) time.Time {
+	t = t.AddDate(sign*d.Years, sign*d.Months, sign*(d.Weeks*7+d.Days))
+	return t.Add(time.Duration(sign) * d.clock())
+}
+
+//times returns d with every component multiplied by k
+func (d Duration) times(k int) Duration {
+	ns := time.Duration(d.Nanoseconds) * time.Duration(k) //as int, this wraps on 32-bit after a few seconds
+	return Duration{
+		Years:       d.Years * k,
+		Months:      d.Months * k,
+		Weeks:       d.Weeks * k,
+		Days:        d.Days * k,
+		Hours:       d.Hours * k,
+		Minutes:     d.Minutes * k,
+		Seconds:     d.Seconds*k + int(ns/time.Second),
+		Nanoseconds: int(ns % time.Second),
+	}
+}
+
+//clock returns the total of the clock components, which unlike the calendar components have fixed lengths
+func (d Duration) clock() time.Duration {
+	return time.Duration(d.Hours)*time.Hour + time.Duration(d.Minutes)*time.Minute +
+		time.Duration(d.Seconds)*time.Second + time.Duration(d.Nanoseconds)
+}
+
+//IsZero reports whether every component of d is zero
+func (d Duration) IsZero() bool {
+	return d == Duration{}
+}
+
+//String formats d as an ISO 8601 duration, omitting zero components
+func (d Duration) String() string {
+	if d.IsZero() {
+		return "P0D"
+	}
+	b := []byte{0x50 /*P*/}
+	b = appendComponent(b, d.Years, 0x59 /*Y*/)
+	b = appendComponent(b, d.Months, 0x4d /*M*/)
+	b = appendComponent(b, d.Weeks, 0x57 /*W*/)
+	b = appendComponent(b, d.Days, 0x44 /*D*/)
+	if d.clock() == 0 {
+		if len(b) == 1 {
+			return "P0D" //Seconds and Nanoseconds that cancel out
+		}
+		return string(b)
+	}
+	b = append(b, 0x54 /*T*/)
+	b = appendComponent(b, d.Hours, 0x48 /*H*/)
+	b = appendComponent(b, d.Minutes, 0x4d /*M*/)
+	if ns := int64(d.Seconds)*1e9 + int64(d.Nanoseconds); ns%1e9 != 0 { //normalises Nanoseconds beyond a second
+		if ns < 0 {
+			b, ns = append(b, 0x2d /*-*/), -ns
+		}
+		b = strconv.AppendInt(b, ns/1e9, 10)
+		f := strconv.FormatInt(1e9+ns%1e9, 10) //leading 1 keeps the leading zeros
+		b = append(b, 0x2e /*.*/)
+		b = append(b, strings.TrimRight(f[1:], "0")...)
+		return string(append(b, 0x53 /*S*/))
+	}
+	return string(appendComponent(b, d.Seconds+d.Nanoseconds/1e9, 0x53 /*S*/))
+}
+
+func appendComponent(b []byte, v int, designator byte) []byte {
+	if v == 0 {
+		return b
+	}
+	return append(strconv.AppendInt(b, int64(v), 10), designator)
+}
+
+func pow10(n int) int {
+	v := 1
+	for ; n > 0; n-- {
+		v *= 10
+	}
+	return v
+}
@@ -0,0 +1,129 @@
+package timeframe
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	testCases := []struct {
+		pat    string
+		d      Duration
+		format string
+	}{
+		{"P1Y2M3DT4H5M6S", Duration{Years: 1, Months: 2, Days: 3, Hours: 4, Minutes: 5, Seconds: 6}, ""},
+		{"P1Y2M3DT4H5M6.5S", Duration{Years: 1, Months: 2, Days: 3, Hours: 4, Minutes: 5, Seconds: 6, Nanoseconds: 500000000}, ""},
+		{"PT0,000000001S", Duration{Nanoseconds: 1}, "PT0.000000001S"},
+		{"PT1.250S", Duration{Seconds: 1, Nanoseconds: 250000000}, "PT1.25S"},
+		{"P2W", Duration{Weeks: 2}, ""},
+		{"P1M", Duration{Months: 1}, ""},
+		{"PT1M", Duration{Minutes: 1}, ""},
+		{"P1W2D", Duration{Weeks: 1, Days: 2}, ""},
+		{"PT36H", Duration{Hours: 36}, ""},
+		{"P0D", Duration{}, ""},
+		{"PT0S", Duration{}, "P0D"},
+		{"P001Y", Duration{Years: 1}, "P1Y"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pat, func(t *testing.T) {
+			d, err := ParseDuration(tc.pat)
+			if err != nil || d != tc.d {
+				t.Fatal(d, err)
+			}
+			format := tc.format
+			if format == "" {
+				format = tc.pat
+			}
+			if d.String() != format {
+				t.Error(d.String())
+			}
+		})
+	}
+}
+
+func TestDurationString(t *testing.T) {
+	testCases := []struct {
+		d   Duration
+		exp string
+	}{
+		{Duration{Nanoseconds: 1500000000}, "PT1.5S"},
+		{Duration{Seconds: 1, Nanoseconds: 2000000000}, "PT3S"},
+		{Duration{Seconds: 2, Nanoseconds: -500000000}, "PT1.5S"},
+		{Duration{Nanoseconds: -1500000000}, "PT-1.5S"},
+		{Duration{Seconds: -1, Nanoseconds: -250000000}, "PT-1.25S"},
+		{Duration{Seconds: 1, Nanoseconds: -1000000000}, "P0D"},
+		{Duration{Days: 1, Seconds: 1, Nanoseconds: -1000000000}, "P1D"},
+		{Duration{Minutes: 1, Nanoseconds: 1000000001}, "PT1M1.000000001S"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.exp, func(t *testing.T) {
+			if s := tc.d.String(); s != tc.exp {
+				t.Error(s)
+			}
+		})
+	}
+}
+
+func TestBadDuration(t *testing.T) {
+	testCases := []struct {
+		pat    string
+		offset int
+		reason error
+	}{
+		{"", 0, ErrNotRecognised},
+		{"1D", 0, ErrNotRecognised},
+		{"P", 1, ErrNotRecognised},
+		{"PT", 2, ErrNotRecognised},
+		{"P1DT", 4, ErrNotRecognised},
+		{"P1D2Y", 4, ErrUnknownUnit},
+		{"P1H", 2, ErrUnknownUnit},
+		{"PT1D", 3, ErrUnknownUnit},
+		{"P1.5D", 2, ErrUnknownUnit},
+		{"PT1.5M", 5, ErrUnknownUnit},
+		{"PT1.S", 4, ErrNotRecognised},
+		{"PT1.0000000001S", 4, ErrNotRecognised},
+		{"P1DT1HT1M", 6, ErrNotRecognised},
+		{"P-1D", 1, ErrNotRecognised},
+		{"P1234567D", 1, ErrNotRecognised},
+		{"P1D ", 3, ErrNotRecognised},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pat, func(t *testing.T) {
+			d, err := ParseDuration(tc.pat)
+			var pe *ParseError
+			if !d.IsZero() || !errors.As(err, &pe) {
+				t.Fatal(d, err)
+			}
+			if pe.Input != tc.pat || pe.Kind != DurationToken || pe.Offset != tc.offset || pe.Reason != tc.reason {
+				t.Errorf("%q %s %d %v", pe.Input, pe.Kind, pe.Offset, pe.Reason)
+			}
+		})
+	}
+}
+
+func TestDurationAddTo(t *testing.T) {
+	const format = "2006-01-02 15:04:05.000"
+	loc, _ := time.LoadLocation("Europe/London")
+	testCases := []struct {
+		from string
+		d    string
+		to   string
+	}{
+		{"2017-01-31 00:00:00.000", "P1M", "2017-03-03 00:00:00.000"}, //AddDate normalises Feb 31st
+		{"2016-02-29 00:00:00.000", "P1Y", "2017-03-01 00:00:00.000"},
+		{"2017-03-25 12:00:00.000", "P1D", "2017-03-26 12:00:00.000"}, //23 hour day
+		{"2017-03-25 12:00:00.000", "PT24H", "2017-03-26 13:00:00.000"},
+		{"2017-03-18 22:50:00.000", "P1WT1M1.5S", "2017-03-25 22:51:01.500"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.d, func(t *testing.T) {
+			d, _ := ParseDuration(tc.d)
+			from, _ := time.ParseInLocation(format, tc.from, loc)
+			to, _ := time.ParseInLocation(format, tc.to, loc)
+			if got := d.AddTo(from); got != to {
+				t.Error(got)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package timeframe
+
+import (
+	"errors"
+	"fmt"
+)
+
+//Reasons a token can be rejected, as carried by ParseError and matched with errors.Is
+var (
+	ErrNotRecognised     = errors.New("timeframe not recognised") //also matches every ParseError
+	ErrBadSeparator      = errors.New("bad separator")
+	ErrYearOutOfRange    = errors.New("year out of range")
+	ErrMonthOutOfRange   = errors.New("month out of range")
+	ErrHalfOutOfRange    = errors.New("half out of range")
+	ErrQuarterOutOfRange = errors.New("quarter out of range")
+	ErrWeekOutOfRange    = errors.New("week out of range")
+	ErrDayOutOfRange     = errors.New("day out of range")
+	ErrHourOutOfRange    = errors.New("hour out of range")
+	ErrMinuteOutOfRange  = errors.New("minute out of range")
+	ErrSecondOutOfRange  = errors.New("second out of range")
+	ErrOffsetOutOfRange  = errors.New("offset out of range")
+	ErrZoneOutOfRange    = errors.New("zone offset out of range")
+	ErrUnknownZone       = errors.New("unknown time zone")
+	ErrUnknownUnit       = errors.New("unknown unit")
+	ErrUnknownModifier   = errors.New("unknown modifier")
+	ErrInvertedRange     = errors.New("range ends before it starts")
+	ErrOverlappingRange  = errors.New("range operands overlap")
+	ErrSeveralRanges     = errors.New("several separate ranges, see RelativeSet")
+)
+
+//TokenKind identifies the family of token a ParseError concerns
+type TokenKind int
+
+const (
+	UnknownToken  TokenKind = iota //too short to tell, or a join like 2017-03..today
+	AbsoluteToken                  //like 2017-03-18
+	RelativeToken                  //like 3_days_ago
+	DurationToken                  //like P1Y2M3D
+	DateMathToken                  //like now-7d/d
+)
+
+func (k TokenKind) String() string {
+	switch k {
+	case AbsoluteToken:
+		return "absolute"
+	case RelativeToken:
+		return "relative"
+	case DurationToken:
+		return "duration"
+	case DateMathToken:
+		return "date math"
+	}
+	return "unknown"
+}
+
+//ParseError describes why a token was rejected and where
+type ParseError struct {
+	Input  string //the token as given
+	Offset int    //byte offset into Input at which the problem was found
+	Kind   TokenKind
+	Reason error //one of the Err values above
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("timeframe: %s %q: %v at offset %d", e.Kind, e.Input, e.Reason, e.Offset)
+}
+
+//Unwrap returns the Reason, so errors.Is(err, ErrMonthOutOfRange) works as expected
+func (e *ParseError) Unwrap() error {
+	return e.Reason
+}
+
+//Is reports every ParseError as ErrNotRecognised, whatever its Reason
+func (e *ParseError) Is(target error) bool {
+	return target == ErrNotRecognised
+}
+
+//fail returns the zero Range alongside a ParseError
+func fail(k TokenKind, s string, i int, reason error) (Range, error) {
+	return Range{}, &ParseError{
+		Input:  s,
+		Offset: i,
+		Kind:   k,
+		Reason: reason,
+	}
+}
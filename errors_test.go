@@ -0,0 +1,63 @@
+package timeframe
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseError(t *testing.T) {
+	testCases := []struct {
+		pat    string
+		kind   TokenKind
+		offset int
+		reason error
+	}{
+		{"", UnknownToken, 0, ErrNotRecognised},
+		{"0000", AbsoluteToken, 0, ErrYearOutOfRange},
+		{"2017-13-01", AbsoluteToken, 5, ErrMonthOutOfRange},
+		{"20171301", AbsoluteToken, 4, ErrMonthOutOfRange},
+		{"2017-02-29", AbsoluteToken, 8, ErrDayOutOfRange},
+		{"2017-366", AbsoluteToken, 5, ErrDayOutOfRange},
+		{"2017-W53", AbsoluteToken, 6, ErrWeekOutOfRange},
+		{"2017-Q5", AbsoluteToken, 6, ErrQuarterOutOfRange},
+		{"2017H3", AbsoluteToken, 5, ErrHalfOutOfRange},
+		{"2017-W11-8", AbsoluteToken, 9, ErrDayOutOfRange},
+		{"2017-03-18T24", AbsoluteToken, 11, ErrHourOutOfRange},
+		{"2017-03-18T22:60", AbsoluteToken, 14, ErrMinuteOutOfRange},
+		{"20170318T225060", AbsoluteToken, 13, ErrSecondOutOfRange},
+		{"2017-03x18", AbsoluteToken, 7, ErrBadSeparator},
+		{"2017-03-18x22", AbsoluteToken, 10, ErrBadSeparator},
+		{"2017-03-18T22:50x42", AbsoluteToken, 16, ErrBadSeparator},
+		{"2017x03", AbsoluteToken, 4, ErrBadSeparator},
+		{"2017-03-18 ", AbsoluteToken, 10, ErrBadSeparator},
+		{"2017-03-18T22:50:42.0123456789", AbsoluteToken, 20, ErrSecondOutOfRange},
+		{"last_1234_days", RelativeToken, 5, ErrOffsetOutOfRange},
+		{"prev_1234_days", RelativeToken, 5, ErrOffsetOutOfRange},
+		{"next_0_days", RelativeToken, 5, ErrOffsetOutOfRange},
+		{"3_fortnights_ago", RelativeToken, 2, ErrUnknownUnit},
+		{"prev_fortnight", RelativeToken, 5, ErrUnknownUnit},
+		{"prev_days", RelativeToken, 5, ErrUnknownUnit},
+		{"last_day", RelativeToken, 0, ErrUnknownModifier},
+		{"3_days_agone", RelativeToken, 7, ErrUnknownModifier},
+		{"before_3_days", RelativeToken, 0, ErrUnknownModifier},
+		{"next_2_weekends", RelativeToken, 5, ErrSeveralRanges},
+		{"previous_3_workweeks", RelativeToken, 9, ErrSeveralRanges},
+		{"last_2_fridays", RelativeToken, 5, ErrSeveralRanges},
+		{"abc_days_ago", RelativeToken, 0, ErrNotRecognised},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pat, func(t *testing.T) {
+			_, err := Expand(tc.pat, nil)
+			var pe *ParseError
+			if !errors.As(err, &pe) {
+				t.Fatalf("%T %v", err, err)
+			}
+			if pe.Input != tc.pat || pe.Kind != tc.kind || pe.Offset != tc.offset || pe.Reason != tc.reason {
+				t.Errorf("%q %s %d %v", pe.Input, pe.Kind, pe.Offset, pe.Reason)
+			}
+			if !errors.Is(err, tc.reason) || !errors.Is(err, ErrNotRecognised) {
+				t.Fail()
+			}
+		})
+	}
+}
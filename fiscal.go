@@ -0,0 +1,78 @@
+package timeframe
+
+import "time"
+
+//FiscalYear describes when fiscal years start, and how they are named
+type FiscalYear struct {
+	Month  time.Month //first month of the fiscal year
+	Day    int        //first day of that month, or of every month that is shorter, so 31 means the last
+	Naming FiscalNaming
+}
+
+//FiscalNaming determines which calendar year gives a fiscal year its name
+type FiscalNaming int
+
+const (
+	NamedByEndYear   FiscalNaming = iota //FY2017 ends in 2017, as with the US federal government
+	NamedByStartYear                     //FY2017 starts in 2017
+)
+
+//first returns the month and day on which fiscal years start, treating zero as the 1st of January
+func (f FiscalYear) first() (time.Month, int) {
+	m, d := f.Month, f.Day
+	if m == 0 {
+		m = time.January
+	}
+	if d == 0 {
+		d = 1
+	}
+	return m, d
+}
+
+//months returns l months of the fiscal year named y, starting o months into it
+func (f FiscalYear) months(y, o, l int, loc *time.Location) Range {
+	m, d := f.first()
+	if f.Naming == NamedByEndYear && (m != time.January || d != 1) {
+		y-- //FY2017 starting in April ends in 2018, so started in 2016
+	}
+	return Range{
+		LowerInc: clamped(y, m+time.Month(o), d, loc),
+		UpperExc: clamped(y, m+time.Month(o+l), d, loc),
+	}
+}
+
+//clamped returns day d of month m of year y, or the last day of that month if it is shorter, where m may overflow
+func clamped(y int, m time.Month, d int, loc *time.Location) time.Time {
+	first := time.Date(y, m, 1, 0, 0, 0, 0, loc)
+	if n := daysIn(first.Year(), int(first.Month())); d > n {
+		d = n
+	}
+	return time.Date(first.Year(), first.Month(), d, 0, 0, 0, 0, loc)
+}
+
+//locate returns the name of the fiscal year containing t, along with how many whole months t is into it
+func (f FiscalYear) locate(t time.Time) (y, o int) {
+	m, d := f.first()
+	y, o = t.Year(), int(t.Month()-m)
+	if n := daysIn(y, int(t.Month())); d > n {
+		d = n //as per clamped
+	}
+	if t.Day() < d {
+		o--
+	}
+	if o < 0 {
+		y, o = y-1, o+12
+	}
+	if f.Naming == NamedByEndYear && (m != time.January || d != 1) {
+		y++
+	}
+	return y, o
+}
+
+//relative returns the Range spanning fiscal years, halves or quarters l to u relative to t
+func (f FiscalYear) relative(dp string, l, u int, t *time.Time) Range {
+	n := unitMonths(dp)
+	y, o := f.locate(*t)
+	o -= o % n //back to the start of the current period
+	return f.months(y, o+l*n, (u-l+1)*n, t.Location())
+}
@@ -0,0 +1,127 @@
+package timeframe
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFiscal(t *testing.T) {
+	const format = "2006-01-02"
+	loc, _ := time.LoadLocation("Europe/London")
+	rel := time.Date(2017, 03, 18, 22, 50, 0, 0, loc)
+	uk := NewParser() //named by start year, as is common where the fiscal year starts in April
+	uk.Fiscal = FiscalYear{Month: time.April, Day: 1, Naming: NamedByStartYear}
+	us := NewParser() //the US federal government
+	us.Fiscal = FiscalYear{Month: time.October, Day: 1, Naming: NamedByEndYear}
+	odd := NewParser()
+	odd.Fiscal = FiscalYear{Month: time.March, Day: 19, Naming: NamedByEndYear}
+	odd.UseFiscal = true
+	end := NewParser() //on the 31st, or the last day of shorter months
+	end.Fiscal = FiscalYear{Month: time.March, Day: 31, Naming: NamedByEndYear}
+	testCases := []struct {
+		p     *Parser
+		pat   string
+		lower string
+		upper string
+	}{
+		{std, "FY2017", "2017-01-01", "2018-01-01"},
+		{std, "FY2017-Q2", "2017-04-01", "2017-07-01"},
+		{std, "this_fiscal_quarter", "2017-01-01", "2017-04-01"},
+		{uk, "FY2017", "2017-04-01", "2018-04-01"},
+		{uk, "FY2017-Q2", "2017-07-01", "2017-10-01"},
+		{uk, "FY2017Q4", "2018-01-01", "2018-04-01"},
+		{uk, "FY2017-H2", "2017-10-01", "2018-04-01"},
+		{uk, "2017", "2017-01-01", "2018-01-01"},
+		{uk, "2017-Q2", "2017-04-01", "2017-07-01"},
+		{uk, "this_fiscal_year", "2016-04-01", "2017-04-01"},
+		{uk, "this_year", "2017-01-01", "2018-01-01"},
+		{uk, "prev_fiscal_quarter", "2016-10-01", "2017-01-01"},
+		{uk, "2_fiscal_quarters_ago", "2016-07-01", "2016-10-01"},
+		{uk, "next_fiscal_half", "2017-04-01", "2017-10-01"},
+		{uk, "last_2_fiscal_halves", "2016-04-01", "2017-04-01"},
+		{uk, "next_3_fiscal_years", "2017-04-01", "2020-04-01"},
+		{uk, "FY2016-Q3/FY2017-Q1", "2016-10-01", "2017-07-01"},
+		{uk, "FY2017-Q1/Q3", "2017-04-01", "2018-01-01"},
+		{us, "FY2017", "2016-10-01", "2017-10-01"},
+		{us, "FY2017-Q1", "2016-10-01", "2017-01-01"},
+		{us, "FY2017-H2", "2017-04-01", "2017-10-01"},
+		{us, "this_fiscal_year", "2016-10-01", "2017-10-01"},
+		{us, "this_fiscal_quarter", "2017-01-01", "2017-04-01"},
+		{us, "prev_fiscal_year", "2015-10-01", "2016-10-01"},
+		{odd, "2017", "2016-03-19", "2017-03-19"},
+		{odd, "2017-Q4", "2016-12-19", "2017-03-19"},
+		{odd, "2017-03", "2017-03-01", "2017-04-01"}, //months are unaffected
+		{odd, "this_year", "2016-03-19", "2017-03-19"},
+		{odd, "next_quarter", "2017-03-19", "2017-06-19"},
+		{odd, "this_fiscal_year", "2016-03-19", "2017-03-19"},
+		{end, "FY2017", "2016-03-31", "2017-03-31"},
+		{end, "FY2017-Q1", "2016-03-31", "2016-06-30"},
+		{end, "FY2017-Q2", "2016-06-30", "2016-09-30"},
+		{end, "FY2017-Q4", "2016-12-31", "2017-03-31"},
+		{end, "FY2017-H2", "2016-09-30", "2017-03-31"},
+		{end, "this_fiscal_quarter", "2016-12-31", "2017-03-31"},
+		{end, "next_fiscal_quarter", "2017-03-31", "2017-06-30"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pat, func(t *testing.T) {
+			var r Range
+			var err error
+			if isDigit(tc.pat[3]) {
+				r, err = tc.p.Absolute(tc.pat, loc)
+			} else {
+				r, err = tc.p.Relative(tc.pat, &rel)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if inc, _ := time.ParseInLocation(format, tc.lower, loc); inc != r.LowerInc {
+				t.Errorf("L %s %s", inc, r.LowerInc)
+			}
+			if exc, _ := time.ParseInLocation(format, tc.upper, loc); exc != r.UpperExc {
+				t.Errorf("U %s %s", exc, r.UpperExc)
+			}
+		})
+	}
+}
+
+func TestBadFiscal(t *testing.T) {
+	patterns := []string{
+		"FY", "FY17", "FX2017", "F2017", "fy2017", "FY2017-", "FY2017-03", "FY201703", "FY2017-W01", "FY2017-077",
+		"FY2017-Q5", "FY2017-H3", "FY0000",
+		"fiscal_year", "this_fiscal", "this_fiscal_day", "this_fiscal_years", "prev_fiscal_fiscal_year",
+		"last_fiscal_year", "fiscal_this_year", "2_fiscal_days_ago",
+	}
+	for _, p := range patterns {
+		t.Run(p, func(t *testing.T) {
+			r, err := Expand(p, nil)
+			if !r.IsZero() || err == nil {
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestFiscalClamped(t *testing.T) {
+	loc := time.UTC
+	p := NewParser()
+	p.Fiscal = FiscalYear{Month: time.November, Day: 30}
+	nov30, feb28, may30 := time.Date(2016, 11, 30, 0, 0, 0, 0, loc), time.Date(2017, 2, 28, 0, 0, 0, 0, loc), time.Date(2017, 5, 30, 0, 0, 0, 0, loc)
+	testCases := []struct {
+		rel   time.Time
+		lower time.Time
+		upper time.Time
+	}{
+		{time.Date(2017, 2, 27, 12, 0, 0, 0, loc), nov30, feb28},
+		{time.Date(2017, 2, 28, 12, 0, 0, 0, loc), feb28, may30}, //the 30th, as near as February gets
+		{time.Date(2017, 5, 29, 12, 0, 0, 0, loc), feb28, may30},
+		{time.Date(2017, 5, 30, 12, 0, 0, 0, loc), may30, time.Date(2017, 8, 30, 0, 0, 0, 0, loc)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.rel.String(), func(t *testing.T) {
+			r, err := p.Relative("this_fiscal_quarter", &tc.rel)
+			if err != nil || r.LowerInc != tc.lower || r.UpperExc != tc.upper {
+				t.Error(r, err)
+			}
+		})
+	}
+}
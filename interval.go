@@ -0,0 +1,137 @@
+package timeframe
+
+import (
+	"strings"
+	"time"
+)
+
+//span is a time interval along with the step by which it repeats
+type span struct {
+	Range
+	step Duration
+	dir  int //+1 to repeat forwards from LowerInc, -1 to repeat backwards from UpperExc
+}
+
+//interval parses the ISO 8601 time interval s[i:], whose solidus is at s[k]
+//
+//Each endpoint expands to the Range of its own token, so the interval runs from the start of the first
+//to the end of the last; 2017-03-01/2017-03-18 includes all of the 18th, as does P2D/2017-03-18
+//
+//The forms are start/end, start/duration and duration/end, where the end of start/end may omit
+//leading components that are the same as the start's, like 2017-03-01/18, and takes the start's zone if it has none
+func (p *Parser) interval(s string, i, k int, loc *time.Location) (span, error) {
+	a, b := s[i:k], s[k+1:]
+	switch {
+	case strings.HasPrefix(a, "P"): //P2D/2017-03-18
+		sc := scanner{s: s[:k], i: i}
+		d := parseDuration(&sc)
+		if _, e := sc.finish(s, Range{}); e != nil {
+			return span{}, e
+		}
+		end, e := p.endpoint(s, k+1, len(s), loc)
+		if e != nil {
+			return span{}, e
+		}
+		r := Range{
+			LowerInc: d.addTo(end.UpperExc, -1),
+			UpperExc: end.UpperExc,
+		}
+		if !r.LowerInc.Before(r.UpperExc) { //P0D/2017-03-01
+			_, e = fail(AbsoluteToken, s, i, ErrInvertedRange)
+			return span{}, e
+		}
+		return span{r, d, -1}, nil
+	case strings.HasPrefix(b, "P"): //2017-03-01T00:00/P1W
+		start, e := p.endpoint(s, i, k, loc)
+		if e != nil {
+			return span{}, e
+		}
+		sc := scanner{s: s, i: k + 1}
+		d := parseDuration(&sc)
+		if _, e := sc.finish(s, Range{}); e != nil {
+			return span{}, e
+		}
+		r := Range{
+			LowerInc: start.LowerInc,
+			UpperExc: d.addTo(start.LowerInc, +1),
+		}
+		if !r.LowerInc.Before(r.UpperExc) { //2017-03-01/PT0S
+			_, e = fail(AbsoluteToken, s, k+1, ErrInvertedRange)
+			return span{}, e
+		}
+		return span{r, d, +1}, nil
+	}
+	start, e := p.endpoint(s, i, k, loc)
+	if e != nil {
+		return span{}, e
+	}
+	za := zoneAt(a, false) //the end takes the start's zone unless it has its own, like 10:00Z/12:00
+	zb := zoneAt(b, strings.IndexByte(a, 0x54 /*T*/) != -1 && strings.IndexByte(b, 0x54 /*T*/) == -1)
+	var end Range
+	if n := za - zb; n > 0 && zb > 0 && sameShape(a[n:za], b[:zb]) { //2017-03-01/18
+		c := a[:n] + b
+		if zb == len(b) {
+			c += a[za:]
+		}
+		end, e = p.endpoint(c, 0, len(c), loc)
+		if pe, ok := e.(*ParseError); ok {
+			pe.Input, pe.Offset = s, k+1+pe.Offset-n
+			if pe.Offset < k+1 {
+				pe.Offset = k + 1
+			}
+		}
+	} else {
+		end, e = p.endpoint(s, k+1, len(s), loc)
+	}
+	if e != nil {
+		return span{}, e
+	}
+	if !start.LowerInc.Before(end.UpperExc) {
+		_, e = fail(AbsoluteToken, s, k+1, ErrInvertedRange)
+		return span{}, e
+	}
+	r := Range{
+		LowerInc: start.LowerInc,
+		UpperExc: end.UpperExc,
+	}
+	d := r.UpperExc.Sub(r.LowerInc) //repeats by elapsed time, whatever the calendar
+	return span{r, Duration{Seconds: int(d / time.Second), Nanoseconds: int(d % time.Second)}, +1}, nil
+}
+
+//endpoint parses the single token s[i:j]
+func (p *Parser) endpoint(s string, i, j int, loc *time.Location) (Range, error) {
+	sc := scanner{s: s[:j], i: i}
+	return sc.finish(s, p.absolute(&sc, loc))
+}
+
+//zoneAt returns where the zone of a token like 2017-03-01T10:00+01 starts, or len(s) if it has none,
+//where clock is whether s is only a time of day, like 10:00+01
+func zoneAt(s string, clock bool) int {
+	t := strings.IndexByte(s, 0x54 /*T*/)
+	if clock {
+		t = 0
+	}
+	switch {
+	case t == -1:
+		return len(s)
+	case strings.HasSuffix(s, "Z"):
+		return len(s) - 1
+	}
+	if k := strings.LastIndexAny(s[t:], "+-"); k != -1 {
+		return t + k
+	}
+	return len(s)
+}
+
+//sameShape reports whether a and b have digits and separators in the same places
+func sameShape(a, b string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := 0; i < len(a); i++ {
+		if isDigit(a[i]) != isDigit(b[i]) || !isDigit(a[i]) && a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
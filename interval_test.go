@@ -0,0 +1,98 @@
+package timeframe
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestInterval(t *testing.T) {
+	const format = "2006-01-02 15:04"
+	loc, _ := time.LoadLocation("Europe/London")
+	testCases := []struct {
+		pat   string
+		lower string
+		upper string
+	}{
+		{"2017-03-01/2017-03-18", "2017-03-01 00:00", "2017-03-19 00:00"},
+		{"20170301/20170318", "2017-03-01 00:00", "2017-03-19 00:00"},
+		{"2017-03-01/2017-04", "2017-03-01 00:00", "2017-05-01 00:00"},
+		{"2017-W11/2017-W12", "2017-03-13 00:00", "2017-03-27 00:00"},
+		{"2017-03-01T10:00/2017-03-01T12:30", "2017-03-01 10:00", "2017-03-01 12:31"},
+		{"2017-03-01/18", "2017-03-01 00:00", "2017-03-19 00:00"},
+		{"2017-02-15/03-14", "2017-02-15 00:00", "2017-03-15 00:00"},
+		{"2017-03-01T10:00/12:30", "2017-03-01 10:00", "2017-03-01 12:31"},
+		{"2017-03-01T00:00/P1W", "2017-03-01 00:00", "2017-03-08 00:00"},
+		{"2017-03-01/P1M", "2017-03-01 00:00", "2017-04-01 00:00"},
+		{"2017-03-25/PT36H", "2017-03-25 00:00", "2017-03-26 13:00"}, //clocks go forward on the 26th
+		{"2017-03-25/P1DT12H", "2017-03-25 00:00", "2017-03-26 13:00"},
+		{"P2D/2017-03-18", "2017-03-17 00:00", "2017-03-19 00:00"},
+		{"PT90M/2017-03-18T12", "2017-03-18 11:30", "2017-03-18 13:00"},
+		{"2017-03-01T10:00Z/12:00", "2017-03-01 10:00", "2017-03-01 12:01"}, //GMT, as London in March
+		{"2017-03-01T10:00Z/12:00Z", "2017-03-01 10:00", "2017-03-01 12:01"},
+		{"2017-03-01T10:00/12:00-01", "2017-03-01 10:00", "2017-03-01 13:01"},
+		{"2017-03-01T10:00Z/12:00+01", "2017-03-01 10:00", "2017-03-01 11:01"},
+		{"2017-03-18T22:50+01/23:00", "2017-03-18 21:50", "2017-03-18 22:01"},
+		{"2017-03-18T22:50+01:00/18T23:00", "2017-03-18 21:50", "2017-03-18 22:01"},
+		{"2017-03-18T22:50-05/23:00", "2017-03-19 03:50", "2017-03-19 04:01"},
+		{"20170318T2250+0100/2300", "2017-03-18 21:50", "2017-03-18 22:01"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pat, func(t *testing.T) {
+			r, err := Expand(tc.pat, loc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if inc, _ := time.ParseInLocation(format, tc.lower, loc); !inc.Equal(r.LowerInc) {
+				t.Errorf("L %s %s", inc, r.LowerInc)
+			}
+			if exc, _ := time.ParseInLocation(format, tc.upper, loc); !exc.Equal(r.UpperExc) {
+				t.Errorf("U %s %s", exc, r.UpperExc)
+			}
+		})
+	}
+}
+
+func TestBadInterval(t *testing.T) {
+	testCases := []struct {
+		pat    string
+		offset int
+		reason error
+	}{
+		{"2017-03-01/", 11, ErrNotRecognised},
+		{"/2017-03-01", 0, ErrNotRecognised},
+		{"2017-03-18/2017-03-01", 11, ErrInvertedRange},
+		{"2017-03-18/01", 11, ErrInvertedRange},
+		{"2017-02-01/30", 11, ErrDayOutOfRange},
+		{"2017-02-01/13-01", 11, ErrMonthOutOfRange},
+		{"2017-13-01/2017-03-01", 5, ErrMonthOutOfRange},
+		{"2017-03-01/2017-03-01/2017-03-02", 21, ErrBadSeparator},
+		{"2017-03-01/P", 12, ErrNotRecognised},
+		{"2017-03-01/PT", 13, ErrNotRecognised},
+		{"2017-03-01/P1DT", 15, ErrNotRecognised},
+		{"2017-03-01/P1H", 13, ErrUnknownUnit},
+		{"2017-03-01/P1D2Y", 15, ErrUnknownUnit},
+		{"2017-03-01/P1DX", 14, ErrNotRecognised},
+		{"2017-03-01/P1234567D", 12, ErrNotRecognised},
+		{"P1D/P1D", 4, ErrNotRecognised},
+		{"2017-03-01/P0D", 11, ErrInvertedRange}, //empty
+		{"2017-03-01T10:00/PT0S", 17, ErrInvertedRange},
+		{"P0D/2017-03-01", 0, ErrInvertedRange},
+		{"P1D2017-03-01", 0, ErrNotRecognised},
+		{"2017-03-01T10:00Z/12:60", 21, ErrMinuteOutOfRange},
+		{"2017-03-01T10:00Z/12:00+24", 24, ErrZoneOutOfRange},
+		{"2017-03-01T12:00-01/12:00Z", 20, ErrInvertedRange},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pat, func(t *testing.T) {
+			r, err := Absolute(tc.pat, nil)
+			var pe *ParseError
+			if !r.IsZero() || !errors.As(err, &pe) {
+				t.Fatal(r, err)
+			}
+			if pe.Input != tc.pat || pe.Offset != tc.offset || pe.Reason != tc.reason {
+				t.Errorf("%q %d %v", pe.Input, pe.Offset, pe.Reason)
+			}
+		})
+	}
+}
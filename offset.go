@@ -0,0 +1,80 @@
+package timeframe
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+//offsetUnits are the units of offsets like +2d, as per date math with the addition of q for quarters
+const offsetUnits = "yqMwdhms"
+
+//offset returns where the offset at the end of s, like the -1y of this_month-1y, starts, or -1 if there is none
+func offset(s string) int {
+	i := len(s) - 1
+	if i < 2 || strings.IndexByte(offsetUnits, s[i]) == -1 {
+		return -1
+	}
+	j := i
+	for j > 0 && isDigit(s[j-1]) {
+		j--
+	}
+	if j == i || j < 2 || s[j-1] != 0x2b /*+*/ && s[j-1] != 0x2d /*-*/ {
+		return -1
+	}
+	return j - 1
+}
+
+//shift parses the token s[:k] and moves it by the offset s[k:]
+//
+//Both bounds move by the same rule, see Expand for how month ends are clamped
+func (p *Parser) shift(s string, k int, loc *time.Location, t *time.Time) (Range, error) {
+	r, e := p.token(s[:k], loc, t)
+	if e != nil {
+		return Range{}, rebase(e, s, 0)
+	}
+	n, _ := strconv.Atoi(s[k+1 : len(s)-1]) //digits only
+	if n > p.MaxOffset {
+		return fail(UnknownToken, s, k+1, ErrOffsetOutOfRange)
+	}
+	if s[k] == 0x2d /*-*/ {
+		n = -n
+	}
+	var y, m, d int
+	var l time.Duration
+	switch s[len(s)-1:] {
+	case "y":
+		y = n
+	case "q":
+		m = n * 3
+	case "M":
+		m = n
+	case "w":
+		d = n * 7
+	case "d":
+		d = n
+	case "h":
+		l = time.Duration(n) * time.Hour
+	case "m":
+		l = time.Duration(n) * time.Minute
+	case "s":
+		l = time.Duration(n) * time.Second
+	}
+	x := Range{
+		LowerInc: addClamped(r.LowerInc, y, m, d).Add(l),
+		UpperExc: addClamped(r.UpperExc, y, m, d).Add(l),
+	}
+	if u := r.UpperExc; u.Hour() == 0 && u.Minute() == 0 && u.Second() == 0 && u.Nanosecond() == 0 {
+		x.UpperExc = addClamped(u.AddDate(0, 0, -1), y, m, d+1).Add(l) //clamp the last day, not the day after it
+	}
+	if !x.LowerInc.Before(x.UpperExc) { //2017-01-30T12:00/2017-01-31T06:00+1M, both clamped to 28 February
+		return fail(UnknownToken, s, k, ErrInvertedRange)
+	}
+	return x, nil
+}
+
+//addClamped is AddDate, but clamps the day to the end of a shorter month rather than overflowing into the next
+func addClamped(t time.Time, y, m, d int) time.Time {
+	c := clamped(t.Year()+y, t.Month()+time.Month(m), t.Day(), t.Location())
+	return time.Date(c.Year(), c.Month(), c.Day()+d, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
@@ -0,0 +1,85 @@
+package timeframe
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOffset(t *testing.T) {
+	const format = "2006-01-02 15:04"
+	loc, _ := time.LoadLocation("Europe/London")
+	p := NewParser()
+	p.Clock = ClockFunc(func() time.Time { return time.Date(2017, 03, 15, 22, 50, 0, 0, loc) })
+	testCases := []struct {
+		pat   string
+		lower string
+		upper string
+	}{
+		{"2017-03-18+2d", "2017-03-20 00:00", "2017-03-21 00:00"},
+		{"2017-03-18-2d", "2017-03-16 00:00", "2017-03-17 00:00"},
+		{"20170318+1w", "2017-03-25 00:00", "2017-03-26 00:00"},
+		{"this_week-1w", "2017-03-06 00:00", "2017-03-13 00:00"},
+		{"this_month-1y", "2016-03-01 00:00", "2016-04-01 00:00"},
+		{"2017-03-1q", "2016-12-01 00:00", "2017-01-01 00:00"},
+		{"2017-Q1+1M", "2017-02-01 00:00", "2017-05-01 00:00"},
+		{"today+36h", "2017-03-16 12:00", "2017-03-17 12:00"},
+		{"2017-03-25T12+24h", "2017-03-26 13:00", "2017-03-26 14:00"}, //clocks go forward on the 26th
+		{"2017-03-25T12+1d", "2017-03-26 12:00", "2017-03-26 13:00"},
+		{"2017-03-18T22:50+10m", "2017-03-18 23:00", "2017-03-18 23:01"},
+		{"2017-03-18T22:50-120s", "2017-03-18 22:48", "2017-03-18 22:49"},
+		{"2017-03-18+1M-1d", "2017-04-17 00:00", "2017-04-18 00:00"}, //applied in turn
+		{"mtd-1M", "2017-02-01 00:00", "2017-02-15 22:50"},
+		{"2017-01-31+1M", "2017-02-28 00:00", "2017-03-01 00:00"}, //clamped to the end of February
+		{"2017-01-30+1M", "2017-02-28 00:00", "2017-03-01 00:00"},
+		{"2017-01-28/2017-01-31+1M", "2017-02-28 00:00", "2017-03-01 00:00"}, //both bounds clamped alike
+		{"2017-01-29/2017-01-31+1M", "2017-02-28 00:00", "2017-03-01 00:00"},
+		{"2016-02-29+1y", "2017-02-28 00:00", "2017-03-01 00:00"},
+		{"2017-03-31-1q", "2016-12-31 00:00", "2017-01-01 00:00"},
+		{"2017-03+0d", "2017-03-01 00:00", "2017-04-01 00:00"},
+		{"2017-03-01/P1D+1y", "2018-03-01 00:00", "2018-03-02 00:00"},
+		{"2017-03-1y..2017-03", "2016-03-01 00:00", "2017-04-01 00:00"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pat, func(t *testing.T) {
+			r, err := p.Expand(tc.pat, loc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if inc, _ := time.ParseInLocation(format, tc.lower, loc); inc != r.LowerInc {
+				t.Errorf("L %s %s", inc, r.LowerInc)
+			}
+			if exc, _ := time.ParseInLocation(format, tc.upper, loc); exc != r.UpperExc {
+				t.Errorf("U %s %s", exc, r.UpperExc)
+			}
+		})
+	}
+}
+
+func TestBadOffset(t *testing.T) {
+	testCases := []struct {
+		pat    string
+		offset int
+		reason error
+	}{
+		{"2017-03-18+1000d", 11, ErrOffsetOutOfRange},
+		{"2017-13-18+1d", 5, ErrMonthOutOfRange},
+		{"next_wek+1d", 5, ErrUnknownUnit},
+		{"2017-03-18+d", 10, ErrBadSeparator},
+		{"2017-03-18+1x", 10, ErrBadSeparator},
+		{"+1d", 0, ErrNotRecognised},
+		{"2017-01-30T12:00/2017-01-31T06:00+1M", 33, ErrInvertedRange}, //both clamped to 28 February
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pat, func(t *testing.T) {
+			r, err := Expand(tc.pat, nil)
+			var pe *ParseError
+			if !r.IsZero() || !errors.As(err, &pe) {
+				t.Fatal(r, err)
+			}
+			if pe.Input != tc.pat || pe.Offset != tc.offset || pe.Reason != tc.reason {
+				t.Errorf("%q %d %v", pe.Input, pe.Offset, pe.Reason)
+			}
+		})
+	}
+}
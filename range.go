@@ -9,6 +9,102 @@ type Range struct {
 }
 
 //IsZero reports whether r represents a range that has been initialised explicitly
-func (r *Range) IsZero() bool {
+func (r Range) IsZero() bool {
 	return r.LowerInc.IsZero() && r.UpperExc.IsZero()
 }
+
+//IsEmpty reports whether r contains no instants at all
+func (r Range) IsEmpty() bool {
+	return !r.LowerInc.Before(r.UpperExc)
+}
+
+//Duration returns the length of r, which is zero when r is empty
+func (r Range) Duration() time.Duration {
+	if r.IsEmpty() {
+		return 0
+	}
+	return r.UpperExc.Sub(r.LowerInc)
+}
+
+//Equal reports whether r and o represent the same instants, regardless of their locations
+func (r Range) Equal(o Range) bool {
+	return r.LowerInc.Equal(o.LowerInc) && r.UpperExc.Equal(o.UpperExc)
+}
+
+//Contains reports whether t falls within r
+func (r Range) Contains(t time.Time) bool {
+	return !t.Before(r.LowerInc) && t.Before(r.UpperExc)
+}
+
+//ContainsRange reports whether every instant of o falls within r
+func (r Range) ContainsRange(o Range) bool {
+	return o.IsEmpty() || !o.LowerInc.Before(r.LowerInc) && !o.UpperExc.After(r.UpperExc)
+}
+
+//Overlaps reports whether r and o share at least one instant
+func (r Range) Overlaps(o Range) bool {
+	return !r.IsEmpty() && !o.IsEmpty() && r.LowerInc.Before(o.UpperExc) && o.LowerInc.Before(r.UpperExc)
+}
+
+//Before reports whether r ends at or before the start of o
+func (r Range) Before(o Range) bool {
+	return !r.UpperExc.After(o.LowerInc)
+}
+
+//After reports whether r starts at or after the end of o
+func (r Range) After(o Range) bool {
+	return o.Before(r)
+}
+
+//Intersect returns the instants common to r and o, and false if there are none
+func (r Range) Intersect(o Range) (Range, bool) {
+	if !r.Overlaps(o) {
+		return Range{}, false
+	}
+	x := r
+	if o.LowerInc.After(x.LowerInc) {
+		x.LowerInc = o.LowerInc
+	}
+	if o.UpperExc.Before(x.UpperExc) {
+		x.UpperExc = o.UpperExc
+	}
+	return x, true
+}
+
+//Union returns the single Range covering r and o, and false when they neither overlap nor abut
+func (r Range) Union(o Range) (Range, bool) {
+	switch {
+	case o.IsEmpty():
+		return r, true
+	case r.IsEmpty():
+		return o, true
+	case r.LowerInc.After(o.UpperExc) || o.LowerInc.After(r.UpperExc):
+		return Range{}, false
+	}
+	x := r
+	if o.LowerInc.Before(x.LowerInc) {
+		x.LowerInc = o.LowerInc
+	}
+	if o.UpperExc.After(x.UpperExc) {
+		x.UpperExc = o.UpperExc
+	}
+	return x, true
+}
+
+//Subtract returns the parts of r not covered by o, of which there are up to two
+func (r Range) Subtract(o Range) []Range {
+	if r.IsEmpty() {
+		return nil
+	}
+	if !r.Overlaps(o) {
+		return []Range{r}
+	}
+	var x []Range
+	if r.LowerInc.Before(o.LowerInc) {
+		x = append(x, Range{LowerInc: r.LowerInc, UpperExc: o.LowerInc})
+	}
+	if o.UpperExc.Before(r.UpperExc) {
+		x = append(x, Range{LowerInc: o.UpperExc, UpperExc: r.UpperExc})
+	}
+	return x
+}
@@ -0,0 +1,142 @@
+package timeframe
+
+import (
+	"testing"
+	"time"
+)
+
+//hours returns the Range from l to u hours past an arbitrary epoch
+func hours(l, u int) Range {
+	epoch := time.Date(2017, 03, 18, 0, 0, 0, 0, time.UTC)
+	return Range{
+		LowerInc: epoch.Add(time.Duration(l) * time.Hour),
+		UpperExc: epoch.Add(time.Duration(u) * time.Hour),
+	}
+}
+
+func TestRangeContains(t *testing.T) {
+	r := hours(2, 4)
+	testCases := []struct {
+		t    time.Time
+		want bool
+	}{
+		{hours(1, 1).LowerInc, false},
+		{hours(2, 2).LowerInc, true}, //lower bound is inclusive
+		{hours(3, 3).LowerInc, true},
+		{hours(4, 4).LowerInc.Add(-time.Nanosecond), true},
+		{hours(4, 4).LowerInc, false}, //upper bound is exclusive
+	}
+	for _, tc := range testCases {
+		t.Run(tc.t.String(), func(t *testing.T) {
+			if r.Contains(tc.t) != tc.want {
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestRangeRelations(t *testing.T) {
+	testCases := []struct {
+		name     string
+		r, o     Range
+		contains bool
+		overlaps bool
+		before   bool
+		after    bool
+	}{
+		{"disjoint", hours(0, 2), hours(3, 5), false, false, true, false},
+		{"adjacent", hours(0, 2), hours(2, 5), false, false, true, false},
+		{"overlapping", hours(0, 3), hours(2, 5), false, true, false, false},
+		{"inside", hours(0, 5), hours(2, 3), true, true, false, false},
+		{"equal", hours(0, 5), hours(0, 5), true, true, false, false},
+		{"outside", hours(2, 3), hours(0, 5), false, true, false, false},
+		{"later", hours(3, 5), hours(0, 3), false, false, false, true},
+		{"empty", hours(0, 5), hours(3, 3), true, false, false, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.r.ContainsRange(tc.o) != tc.contains {
+				t.Error("ContainsRange")
+			}
+			if tc.r.Overlaps(tc.o) != tc.overlaps || tc.o.Overlaps(tc.r) != tc.overlaps {
+				t.Error("Overlaps")
+			}
+			if tc.r.Before(tc.o) != tc.before || tc.o.After(tc.r) != tc.before {
+				t.Error("Before")
+			}
+			if tc.r.After(tc.o) != tc.after || tc.o.Before(tc.r) != tc.after {
+				t.Error("After")
+			}
+		})
+	}
+}
+
+func TestRangeIntersectUnion(t *testing.T) {
+	testCases := []struct {
+		name      string
+		r, o      Range
+		intersect Range
+		union     Range
+	}{
+		{"disjoint", hours(0, 2), hours(3, 5), Range{}, Range{}},
+		{"adjacent", hours(0, 2), hours(2, 5), Range{}, hours(0, 5)},
+		{"overlapping", hours(0, 3), hours(2, 5), hours(2, 3), hours(0, 5)},
+		{"inside", hours(0, 5), hours(2, 3), hours(2, 3), hours(0, 5)},
+		{"equal", hours(0, 5), hours(0, 5), hours(0, 5), hours(0, 5)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, pair := range [][2]Range{{tc.r, tc.o}, {tc.o, tc.r}} {
+				i, ok := pair[0].Intersect(pair[1])
+				if ok == tc.intersect.IsZero() || i != tc.intersect {
+					t.Errorf("Intersect %v %v", i, ok)
+				}
+				u, ok := pair[0].Union(pair[1])
+				if ok == tc.union.IsZero() || u != tc.union {
+					t.Errorf("Union %v %v", u, ok)
+				}
+			}
+		})
+	}
+}
+
+func TestRangeSubtract(t *testing.T) {
+	testCases := []struct {
+		name string
+		r, o Range
+		want []Range
+	}{
+		{"disjoint", hours(0, 2), hours(3, 5), []Range{hours(0, 2)}},
+		{"adjacent", hours(0, 2), hours(2, 5), []Range{hours(0, 2)}},
+		{"overlapping", hours(0, 3), hours(2, 5), []Range{hours(0, 2)}},
+		{"overlapped", hours(2, 5), hours(0, 3), []Range{hours(3, 5)}},
+		{"inside", hours(0, 5), hours(2, 3), []Range{hours(0, 2), hours(3, 5)}},
+		{"outside", hours(2, 3), hours(0, 5), nil},
+		{"equal", hours(0, 5), hours(0, 5), nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			x := tc.r.Subtract(tc.o)
+			if len(x) != len(tc.want) {
+				t.Fatal(x)
+			}
+			for i := range x {
+				if x[i] != tc.want[i] {
+					t.Error(x)
+				}
+			}
+		})
+	}
+}
+
+func TestRangeDurationEqual(t *testing.T) {
+	ny, _ := time.LoadLocation("America/New_York")
+	r := hours(2, 5)
+	o := Range{LowerInc: r.LowerInc.In(ny), UpperExc: r.UpperExc.In(ny)}
+	if r.Duration() != 3*time.Hour || hours(5, 2).Duration() != 0 {
+		t.Error("Duration")
+	}
+	if !r.Equal(o) || r == o || r.Equal(hours(2, 4)) {
+		t.Error("Equal")
+	}
+}
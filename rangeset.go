@@ -0,0 +1,164 @@
+package timeframe
+
+import (
+	"sort"
+	"time"
+)
+
+//RangeSet represents a sorted collection of disjoint Ranges, such as business hours over several days
+//
+//Ranges that overlap or abut are merged, so every RangeSet has a single normalised form
+//The zero value is the empty set
+type RangeSet struct {
+	ranges []Range
+}
+
+//NewRangeSet returns the set covering every instant of rs, which may overlap and be in any order
+func NewRangeSet(rs ...Range) RangeSet {
+	sorted := make([]Range, 0, len(rs))
+	for _, r := range rs {
+		if !r.IsEmpty() {
+			sorted = append(sorted, r)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].LowerInc.Before(sorted[j].LowerInc)
+	})
+	var x []Range
+	for _, r := range sorted {
+		x = appendMerged(x, r)
+	}
+	return RangeSet{x}
+}
+
+//appendMerged adds r to x, extending the last Range of x if r overlaps or abuts it
+//
+//r must not start before the last Range of x
+func appendMerged(x []Range, r Range) []Range {
+	if n := len(x); n > 0 && !x[n-1].UpperExc.Before(r.LowerInc) {
+		if r.UpperExc.After(x[n-1].UpperExc) {
+			x[n-1].UpperExc = r.UpperExc
+		}
+		return x
+	}
+	return append(x, r)
+}
+
+//Len returns the number of disjoint Ranges in s
+func (s RangeSet) Len() int {
+	return len(s.ranges)
+}
+
+//At returns the i'th Range of s, in ascending order
+func (s RangeSet) At(i int) Range {
+	return s.ranges[i]
+}
+
+//Ranges returns a copy of the disjoint Ranges of s, in ascending order
+func (s RangeSet) Ranges() []Range {
+	return append([]Range(nil), s.ranges...)
+}
+
+//IsEmpty reports whether s contains no instants at all
+func (s RangeSet) IsEmpty() bool {
+	return len(s.ranges) == 0
+}
+
+//Duration returns the total length of the Ranges in s
+func (s RangeSet) Duration() time.Duration {
+	var d time.Duration
+	for _, r := range s.ranges {
+		d += r.Duration()
+	}
+	return d
+}
+
+//Equal reports whether s and o represent the same instants
+func (s RangeSet) Equal(o RangeSet) bool {
+	if len(s.ranges) != len(o.ranges) {
+		return false
+	}
+	for i := range s.ranges {
+		if !s.ranges[i].Equal(o.ranges[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+//search returns the index of the first Range of s that ends after t
+func (s RangeSet) search(t time.Time) int {
+	return sort.Search(len(s.ranges), func(i int) bool {
+		return s.ranges[i].UpperExc.After(t)
+	})
+}
+
+//Contains reports whether t falls within s
+func (s RangeSet) Contains(t time.Time) bool {
+	i := s.search(t)
+	return i < len(s.ranges) && s.ranges[i].Contains(t)
+}
+
+//ContainsRange reports whether every instant of r falls within s
+func (s RangeSet) ContainsRange(r Range) bool {
+	if r.IsEmpty() {
+		return true
+	}
+	i := s.search(r.LowerInc)
+	return i < len(s.ranges) && s.ranges[i].ContainsRange(r)
+}
+
+//Union returns the set of instants in either s or o
+func (s RangeSet) Union(o RangeSet) RangeSet {
+	a, b := s.ranges, o.ranges
+	x := make([]Range, 0, len(a)+len(b))
+	for len(a) > 0 || len(b) > 0 {
+		if len(b) == 0 || len(a) > 0 && a[0].LowerInc.Before(b[0].LowerInc) {
+			x, a = appendMerged(x, a[0]), a[1:]
+		} else {
+			x, b = appendMerged(x, b[0]), b[1:]
+		}
+	}
+	return RangeSet{x}
+}
+
+//Intersect returns the set of instants in both s and o
+func (s RangeSet) Intersect(o RangeSet) RangeSet {
+	a, b := s.ranges, o.ranges
+	var x []Range
+	for len(a) > 0 && len(b) > 0 {
+		if r, ok := a[0].Intersect(b[0]); ok {
+			x = append(x, r)
+		}
+		if a[0].UpperExc.Before(b[0].UpperExc) {
+			a = a[1:]
+		} else {
+			b = b[1:]
+		}
+	}
+	return RangeSet{x}
+}
+
+//Subtract returns the set of instants in s but not in o
+func (s RangeSet) Subtract(o RangeSet) RangeSet {
+	b := o.ranges
+	var x []Range
+	for _, r := range s.ranges {
+		for len(b) > 0 && !b[0].UpperExc.After(r.LowerInc) {
+			b = b[1:] //skip those wholly before r
+		}
+		for _, c := range b {
+			if !c.LowerInc.Before(r.UpperExc) {
+				break
+			}
+			if c.LowerInc.After(r.LowerInc) {
+				x = append(x, Range{LowerInc: r.LowerInc, UpperExc: c.LowerInc})
+			}
+			r.LowerInc = c.UpperExc
+		}
+		if r.LowerInc.Before(r.UpperExc) {
+			x = append(x, r)
+		}
+	}
+	return RangeSet{x}
+}
@@ -0,0 +1,90 @@
+package timeframe
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewRangeSet(t *testing.T) {
+	testCases := []struct {
+		name string
+		in   []Range
+		want []Range
+	}{
+		{"none", nil, nil},
+		{"empty", []Range{hours(3, 3), hours(5, 2)}, nil},
+		{"sorted", []Range{hours(4, 5), hours(0, 1), hours(2, 3)}, []Range{hours(0, 1), hours(2, 3), hours(4, 5)}},
+		{"adjacent", []Range{hours(2, 3), hours(0, 1), hours(1, 2)}, []Range{hours(0, 3)}},
+		{"overlapping", []Range{hours(2, 6), hours(0, 3), hours(4, 5)}, []Range{hours(0, 6)}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewRangeSet(tc.in...)
+			if !s.Equal(RangeSet{tc.want}) || s.Len() != len(tc.want) {
+				t.Error(s.Ranges())
+			}
+		})
+	}
+}
+
+func TestRangeSetAlgebra(t *testing.T) {
+	testCases := []struct {
+		name      string
+		s, o      []Range
+		union     []Range
+		intersect []Range
+		subtract  []Range
+	}{
+		{"empty", nil, []Range{hours(0, 1)}, []Range{hours(0, 1)}, nil, nil},
+		{"disjoint", []Range{hours(0, 1), hours(4, 5)}, []Range{hours(2, 3)},
+			[]Range{hours(0, 1), hours(2, 3), hours(4, 5)}, nil, []Range{hours(0, 1), hours(4, 5)}},
+		{"adjacent", []Range{hours(0, 1), hours(2, 3)}, []Range{hours(1, 2)},
+			[]Range{hours(0, 3)}, nil, []Range{hours(0, 1), hours(2, 3)}},
+		{"spanning", []Range{hours(0, 2), hours(3, 5), hours(6, 8)}, []Range{hours(1, 7)},
+			[]Range{hours(0, 8)}, []Range{hours(1, 2), hours(3, 5), hours(6, 7)}, []Range{hours(0, 1), hours(7, 8)}},
+		{"holes", []Range{hours(0, 10)}, []Range{hours(1, 2), hours(3, 4), hours(9, 12)},
+			[]Range{hours(0, 12)}, []Range{hours(1, 2), hours(3, 4), hours(9, 10)}, []Range{hours(0, 1), hours(2, 3), hours(4, 9)}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, o := NewRangeSet(tc.s...), NewRangeSet(tc.o...)
+			if u := s.Union(o); !u.Equal(NewRangeSet(tc.union...)) || !u.Equal(o.Union(s)) {
+				t.Error("Union", u.Ranges())
+			}
+			if i := s.Intersect(o); !i.Equal(NewRangeSet(tc.intersect...)) || !i.Equal(o.Intersect(s)) {
+				t.Error("Intersect", i.Ranges())
+			}
+			if x := s.Subtract(o); !x.Equal(NewRangeSet(tc.subtract...)) {
+				t.Error("Subtract", x.Ranges())
+			}
+		})
+	}
+}
+
+func TestRangeSetMembership(t *testing.T) {
+	s := NewRangeSet(hours(0, 2), hours(4, 6), hours(8, 10))
+	if s.Duration() != 6*time.Hour {
+		t.Error("Duration")
+	}
+	for h, want := range []bool{true, true, false, false, true, true, false, false, true, true, false} {
+		if s.Contains(hours(h, h).LowerInc) != want {
+			t.Error("Contains", h)
+		}
+	}
+	testCases := []struct {
+		r    Range
+		want bool
+	}{
+		{hours(0, 2), true},
+		{hours(4, 5), true},
+		{hours(1, 5), false},
+		{hours(2, 4), false},
+		{hours(9, 11), false},
+		{hours(3, 3), true},
+	}
+	for _, tc := range testCases {
+		if s.ContainsRange(tc.r) != tc.want {
+			t.Error("ContainsRange", tc.r)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package timeframe
+
+import (
+	"strings"
+	"time"
+)
+
+//Recurrence iterates the Ranges of an ISO 8601 repeating interval like R5/2017-03-01T00:00/P1D
+//
+//Intervals given as start/duration or start/end repeat forwards, the latter by the elapsed time between
+//its endpoints, whereas those given as duration/end repeat backwards from their end
+type Recurrence struct {
+	x span
+	n int //total number of Ranges, -1 when unbounded
+	i int //number of Ranges returned so far
+}
+
+//Repeating parses a repeating interval like R5/2017-03-01T00:00/P1D and returns an iterator of its Ranges
+func Repeating(s string, loc *time.Location) (*Recurrence, error) {
+	return std.Repeating(s, loc)
+}
+
+//Repeating parses a repeating interval like R5/2017-03-01T00:00/P1D and returns an iterator of its Ranges
+//
+//R5/ yields five Ranges, whereas R/ yields Ranges for as long as Next is called
+func (p *Parser) Repeating(s string, loc *time.Location) (*Recurrence, error) {
+	sc := scanner{s: s}
+	if !sc.skip(0x52 /*R*/) {
+		sc.failAt(0, ErrNotRecognised)
+	}
+	n := -1
+	if sc.digits() > 0 {
+		n = sc.run(9, ErrOffsetOutOfRange)
+	}
+	sc.expect(0x2f /*/*/)
+	k := strings.IndexByte(s[sc.i:], 0x2f /*/*/)
+	if k == -1 {
+		sc.failAt(len(s), ErrNotRecognised) //the interval needs a solidus of its own
+	}
+	if e := sc.err(s); e != nil {
+		return nil, e
+	}
+	x, e := p.interval(s, sc.i, sc.i+k, p.location(loc))
+	if e != nil {
+		return nil, e
+	}
+	return &Recurrence{x: x, n: n}, nil
+}
+
+//Next returns the next Range, or false once every repetition has been returned
+func (r *Recurrence) Next() (Range, bool) {
+	if r.n != -1 && r.i >= r.n {
+		return Range{}, false
+	}
+	a, b := r.x.step.times(r.i), r.x.step.times(r.i+1) //from the anchor each time, so month ends do not drift
+	r.i++
+	if r.x.dir < 0 {
+		return Range{
+			LowerInc: b.addTo(r.x.UpperExc, -1),
+			UpperExc: a.addTo(r.x.UpperExc, -1),
+		}, true
+	}
+	return Range{
+		LowerInc: a.addTo(r.x.LowerInc, +1),
+		UpperExc: b.addTo(r.x.LowerInc, +1),
+	}, true
+}
@@ -0,0 +1,122 @@
+package timeframe
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRepeating(t *testing.T) {
+	const format = "2006-01-02 15:04"
+	loc, _ := time.LoadLocation("Europe/London")
+	testCases := []struct {
+		pat    string
+		ranges [][2]string
+	}{
+		{"R3/2017-03-01T00:00/P1D", [][2]string{
+			{"2017-03-01 00:00", "2017-03-02 00:00"},
+			{"2017-03-02 00:00", "2017-03-03 00:00"},
+			{"2017-03-03 00:00", "2017-03-04 00:00"},
+		}},
+		{"R0/2017-03-01/P1D", nil},
+		{"R4/2017-01-31/P1M", [][2]string{ //each repetition counts from the start, so does not drift
+			{"2017-01-31 00:00", "2017-03-03 00:00"},
+			{"2017-03-03 00:00", "2017-03-31 00:00"},
+			{"2017-03-31 00:00", "2017-05-01 00:00"},
+			{"2017-05-01 00:00", "2017-05-31 00:00"},
+		}},
+		{"R2/2017-03-25T12/2017-03-26T11", [][2]string{ //elapsed time of 23 hours, as the clocks go forward
+			{"2017-03-25 12:00", "2017-03-26 12:00"},
+			{"2017-03-26 12:00", "2017-03-27 11:00"},
+		}},
+		{"R2/P1W/2017-03-18", [][2]string{ //backwards from the end
+			{"2017-03-12 00:00", "2017-03-19 00:00"},
+			{"2017-03-05 00:00", "2017-03-12 00:00"},
+		}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pat, func(t *testing.T) {
+			it, err := Repeating(tc.pat, loc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tc.ranges {
+				r, ok := it.Next()
+				if !ok {
+					t.Fatal("too few")
+				}
+				if inc, _ := time.ParseInLocation(format, want[0], loc); inc != r.LowerInc {
+					t.Errorf("L %s %s", inc, r.LowerInc)
+				}
+				if exc, _ := time.ParseInLocation(format, want[1], loc); exc != r.UpperExc {
+					t.Errorf("U %s %s", exc, r.UpperExc)
+				}
+			}
+			if r, ok := it.Next(); ok || !r.IsZero() {
+				t.Error("too many")
+			}
+		})
+	}
+}
+
+func TestRepeatingUnbounded(t *testing.T) {
+	it, err := Repeating("R/2017-W01/P1W", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev, _ := it.Next()
+	for i := 1; i < 1000; i++ {
+		r, ok := it.Next()
+		if !ok || r.LowerInc != prev.UpperExc || r.Duration() != 7*24*time.Hour {
+			t.Fatal(i, r)
+		}
+		prev = r
+	}
+}
+
+func TestRepeatingFraction(t *testing.T) {
+	it, err := Repeating("R8/2017-03-01T00:00:00/PT0.5S", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2017, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 8; i++ {
+		r, ok := it.Next()
+		if want := start.Add(time.Duration(i) * 500 * time.Millisecond); !ok || r.LowerInc != want || r.Duration() != 500*time.Millisecond {
+			t.Fatal(i, r)
+		}
+	}
+}
+
+func TestBadRepeating(t *testing.T) {
+	testCases := []struct {
+		pat    string
+		offset int
+		reason error
+	}{
+		{"", 0, ErrNotRecognised},
+		{"2017-03-01/P1D", 0, ErrNotRecognised},
+		{"R", 1, ErrBadSeparator},
+		{"R5", 2, ErrBadSeparator},
+		{"Rx/2017-03-01/P1D", 1, ErrBadSeparator},
+		{"R5/2017-03-01", 13, ErrNotRecognised},
+		{"R1234567890/2017-03-01/P1D", 1, ErrOffsetOutOfRange},
+		{"R5/2017-03-32/P1D", 11, ErrDayOutOfRange},
+		{"R5/2017-03-01/P1X", 16, ErrUnknownUnit},
+		{"R5/P1D/P1D", 7, ErrNotRecognised},
+		{"R/2017-03-01/PT0S", 13, ErrInvertedRange}, //would never move
+		{"R/P0D/2017-03-01", 2, ErrInvertedRange},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pat, func(t *testing.T) {
+			it, err := Repeating(tc.pat, nil)
+			var pe *ParseError
+			if it != nil || !errors.As(err, &pe) {
+				t.Fatal(it, err)
+			}
+			if pe.Input != tc.pat || pe.Offset != tc.offset || pe.Reason != tc.reason {
+				t.Errorf("%q %d %v", pe.Input, pe.Offset, pe.Reason)
+			}
+		})
+	}
+}
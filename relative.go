@@ -8,62 +8,184 @@ import (
 
 //Relative parses a token like 3_days_ago and returns the Range it represents
 func Relative(s string, t *time.Time) (Range, error) {
-	if len(s) < 5 || len(s) > 20 { //today, previous_999_minutes
-		return err() //cannot be a valid structure
+	return std.Relative(s, t)
+}
+
+//Relative parses a token like 3_days_ago and returns the Range it represents
+//
+//A nil t is taken to mean the Parser's Clock, in the Parser's default location
+//
+//Weekdays name single days within a week, so this_friday is the Friday of the week containing t,
+//prev_friday that of the week before and 2_fridays_ahead that of the week after next, with weeks starting
+//on the Parser's FirstWeekday; last_friday is the latest Friday before t's day, whichever week it falls in
+//
+//A weekend is the Saturday of such a week and the Sunday after it, and a workweek is its Monday to Friday.
+//More than one of them, or of a weekday, like next_2_weekends, gives ErrSeveralRanges as they are not contiguous;
+//use RelativeSet for those
+//
+//Rolling tokens like rolling_24_hours and past_7_days run for that long up to t itself, ignoring unit boundaries
+//
+//To-date tokens like mtd, ytd, today_so_far and fiscal_quarter_to_date run from the start of the unit containing t
+//up to t itself, truncated to the Parser's ToDatePrecision
+func (p *Parser) Relative(s string, t *time.Time) (Range, error) {
+	if t == nil {
+		now := p.now().In(p.location(nil))
+		t = &now
+	}
+	x, e := p.relative(s)
+	if e != nil {
+		return Range{}, e
 	}
+	return p.resolve(s, x, t)
+}
+
+//RelativeSet parses a token like next_2_weekends and returns the set of Ranges it represents
+func RelativeSet(s string, t *time.Time) (RangeSet, error) {
+	return std.RelativeSet(s, t)
+}
+
+//RelativeSet parses a token like next_2_weekends and returns the set of Ranges it represents
+//
+//Unlike Relative it accepts several weekends, workweeks or weekdays, like prev_2_weekends, which are not
+//contiguous; any other token gives a set holding just the Range that Relative would return
+func (p *Parser) RelativeSet(s string, t *time.Time) (RangeSet, error) {
 	if t == nil {
-		now := time.Now()
+		now := p.now().In(p.location(nil))
 		t = &now
 	}
-	i := strings.IndexByte(s, 0x5f /*_*/)
-	if i == -1 {
+	x, e := p.relative(s)
+	if e != nil {
+		return RangeSet{}, e
+	}
+	wd, n, ok := days(x.dp.s)
+	if !ok || x.sofar || x.rolling {
+		r, e := p.resolve(s, x, t)
+		if e != nil {
+			return RangeSet{}, e
+		}
+		return NewRangeSet(r), nil
+	}
+	latest := x.vs.s == "last" && n == 1
+	rs := make([]Range, 0, x.u-x.l+1)
+	for k := x.l; k <= x.u; k++ {
+		rs = append(rs, p.weekday(wd, n, latest, k, t))
+	}
+	return NewRangeSet(rs...), nil
+}
+
+//relative parses s into its unit and the span of units relative to the reference time
+func (p *Parser) relative(s string) (rel, error) {
+	if dp, ok := toDate(s); ok { //mtd, month_to_date
+		return rel{dp: word{dp, 0}, sofar: true}, nil
+	}
+	if len(s) < 5 { //today, no longest as that depends on MaxOffset
+		return invalid(s, 0, ErrNotRecognised) //cannot be a valid structure
+	}
+	w, n, i := split(s)
+	if i != -1 {
+		return invalid(s, i, ErrNotRecognised) //empty or too many words
+	}
+	for k := 0; k < n-1; k++ {
+		if w[k].s == "fiscal" { //this_fiscal_year, treated as a single unit
+			w[k].s = s[w[k].i : w[k+1].i+len(w[k+1].s)]
+			copy(w[k+1:], w[k+2:])
+			n--
+			break
+		}
+	}
+	switch n {
+	case 1:
 		switch s {
 		case "yesterday":
-			return newRange("day", -1, -1, t)
+			return rel{dp: word{"day", 0}, l: -1, u: -1}, nil
 		case "today":
-			return newRange("day", 0, 0, t)
+			return rel{dp: word{"day", 0}}, nil
 		case "tomorrow":
-			return newRange("day", +1, +1, t)
-		default:
-			return err()
+			return rel{dp: word{"day", 0}, l: +1, u: +1}, nil
 		}
-	}
-	j := strings.LastIndexByte(s, 0x5f /*_*/)
-	if i == j { //prev_day
-		vs := s[:i]
-		dp := s[j+1:]
-		if len(vs) == 0 || len(dp) == 0 || dp[len(dp)-1] == 0x73 /*s*/ || vs == "last" {
-			return err() //disallow s suffix, last_xxx
+	case 2: //prev_day
+		vs, dp := w[0], w[1]
+		if dp.s[len(dp.s)-1] == 0x73 /*s*/ && dp.s != "ms" {
+			return invalid(s, dp.i, ErrUnknownUnit) //disallow s suffix
 		}
-		return slice(dp, vs, 1, t)
-	}
-	{ //1_day_ago, prev_1_day
-		ns := s[:i]
-		dp := s[i+1 : j]
-		vs := s[j+1:]
-		if len(ns) == 0 || len(dp) == 0 || len(vs) == 0 {
-			return err()
+		if _, ok := weekday(dp.s); vs.s == "last" && !ok {
+			return invalid(s, vs.i, ErrUnknownModifier) //disallow last_xxx, other than last_monday
 		}
-		if vs[0] != 0x61 /*a*/ { //prev_1_day
+		return slice(s, dp, vs, 1)
+	case 3: //1_day_ago, prev_1_day
+		ns, dp, vs := w[0], w[1], w[2]
+		if vs.s[0] != 0x61 /*a*/ { //prev_1_day
 			vs, ns, dp = ns, dp, vs
 		}
-		n, e := strconv.Atoi(ns)
-		if e != nil || n < 0 || n > maxOffset {
-			return err() //require n be 0-maxOffset
+		n, e := strconv.Atoi(ns.s)
+		if e != nil {
+			return invalid(s, ns.i, ErrNotRecognised)
+		}
+		if n < 0 || n > p.MaxOffset {
+			return invalid(s, ns.i, ErrOffsetOutOfRange) //require n be 0-MaxOffset
+		}
+		if n == 0 && vs.s[0] != 0x61 /*a*/ {
+			return invalid(s, ns.i, ErrOffsetOutOfRange) //0_days_ago is allowed, last_0_days isn't
+		}
+		switch {
+		case dp.s == "ms":
+		case strings.HasSuffix(dp.s, "halves"):
+			dp.s = dp.s[:len(dp.s)-3] + "f"
+		case strings.HasSuffix(dp.s, "s"):
+			dp.s = dp.s[:len(dp.s)-1] //remove s suffix
 		}
-		if n == 0 && vs[0] != 0x61 /*a*/ {
-			return err() //0_days_ago is allowed, last_0_days isn't
+		x, e := slice(s, dp, vs, n)
+		x.ni = ns.i
+		return x, e
+	}
+	return invalid(s, 0, ErrNotRecognised)
+}
+
+//split breaks s into underscore separated words, returning the offset of any that is empty or one too many
+func split(s string) ([4]word, int, int) {
+	var w [4]word
+	n := 0
+	for i := 0; ; n++ {
+		j := strings.IndexByte(s[i:], 0x5f /*_*/)
+		if j == -1 {
+			j = len(s) - i
 		}
-		if dp[len(dp)-1] == 0x73 /*s*/ {
-			dp = dp[:len(dp)-1] //remove s suffix
+		if j == 0 || n == len(w) {
+			return w, n, i
+		}
+		w[n] = word{s[i : i+j], i}
+		i += j + 1
+		if i > len(s) {
+			return w, n + 1, -1
 		}
-		return slice(dp, vs, n, t)
 	}
 }
 
-func slice(dp, vs string, n int, t *time.Time) (Range, error) {
+//word is a piece of a relative token, along with its offset for error reporting
+type word struct {
+	s string
+	i int
+}
+
+//rel is a parsed relative token, spanning units l to u of dp relative to the reference time
+type rel struct {
+	dp, vs  word
+	ni      int //offset of the number, if any
+	l, u    int
+	sofar   bool //ends at the reference time rather than the end of the unit
+	rolling bool //ends at the reference time and is n units long, whatever the unit boundaries
+}
+
+//invalid returns the zero rel alongside a ParseError
+func invalid(s string, i int, reason error) (rel, error) {
+	_, e := fail(RelativeToken, s, i, reason)
+	return rel{}, e
+}
+
+//slice applies the modifier vs to n units of dp
+func slice(s string, dp, vs word, n int) (rel, error) {
 	var l, u int
-	switch vs {
+	switch vs.s {
 	case "ago":
 		l, u = -n, -n
 	case "ahead":
@@ -76,31 +198,191 @@ func slice(dp, vs string, n int, t *time.Time) (Range, error) {
 		l, u = 0, n-1
 	case "next":
 		l, u = 1, n
+	case "rolling", "past":
+		return rel{dp: dp, vs: vs, l: -n, u: -1, rolling: true}, nil
+	default:
+		return invalid(s, vs.i, ErrUnknownModifier)
+	}
+	return rel{dp: dp, vs: vs, l: l, u: u}, nil
+}
+
+//resolve returns the single Range that x represents relative to t
+func (p *Parser) resolve(s string, x rel, t *time.Time) (Range, error) {
+	if x.rolling {
+		r, e := rolling(x.dp.s, x.u-x.l+1, *t)
+		if e != nil {
+			return fail(RelativeToken, s, x.dp.i, e)
+		}
+		return r, nil
+	}
+	if wd, n, ok := days(x.dp.s); ok && !x.sofar {
+		if x.l != x.u {
+			return fail(RelativeToken, s, x.ni, ErrSeveralRanges) //prev_2_mondays is not a single Range, see RelativeSet
+		}
+		return p.weekday(wd, n, x.vs.s == "last" && n == 1, x.l, t), nil
+	}
+	r, e := p.newRange(x.dp.s, x.l, x.u, t)
+	if e != nil {
+		return fail(RelativeToken, s, x.dp.i, e)
+	}
+	if x.sofar {
+		r.UpperExc = *t
+		if p.ToDatePrecision > 0 {
+			r.UpperExc = truncate(*t, p.ToDatePrecision)
+		}
+		if r.UpperExc.Before(r.LowerInc) {
+			r.UpperExc = r.LowerInc //empty rather than inverted, as when the clocks go back
+		}
+	}
+	return r, nil
+}
+
+//truncate rounds t down to a multiple of d on its own wall clock, whereas time.Truncate works in UTC
+func truncate(t time.Time, d time.Duration) time.Time {
+	w := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC).Truncate(d)
+	return time.Date(w.Year(), w.Month(), w.Day(), w.Hour(), w.Minute(), w.Second(), w.Nanosecond(), t.Location())
+}
+
+//rolling returns the window of n units of dp that ends at t, or ErrUnknownUnit
+//
+//Calendar units are subtracted with AddDate, so the month before 31 March starts on 3 March (not 28 February)
+func rolling(dp string, n int, t time.Time) (Range, error) {
+	var d time.Duration
+	switch dp {
+	case "ms", "millisecond":
+		d = time.Millisecond
+	case "sec", "second":
+		d = time.Second
+	case "min", "minute":
+		d = time.Minute
+	case "hour":
+		d = time.Hour
+	case "day":
+		return Range{LowerInc: t.AddDate(0, 0, -n), UpperExc: t}, nil
+	case "week":
+		return Range{LowerInc: t.AddDate(0, 0, -n*7), UpperExc: t}, nil
+	case "month", "quarter", "half", "year", "fiscal_quarter", "fiscal_half", "fiscal_year":
+		return Range{LowerInc: t.AddDate(0, -n*unitMonths(strings.TrimPrefix(dp, "fiscal_")), 0), UpperExc: t}, nil
 	default:
-		return err()
+		return Range{}, ErrUnknownUnit
 	}
-	return newRange(dp, l, u, t)
+	return Range{LowerInc: t.Add(-d * time.Duration(n)), UpperExc: t}, nil
 }
 
-func newRange(dp string, l, u int, t *time.Time) (Range, error) {
+//toDate returns the unit of a to-date token like mtd or month_to_date
+func toDate(s string) (string, bool) {
+	switch s {
+	case "today_so_far":
+		return "day", true
+	case "wtd":
+		return "week", true
+	case "mtd":
+		return "month", true
+	case "qtd":
+		return "quarter", true
+	case "ytd":
+		return "year", true
+	}
+	if n := len(s) - len("_to_date"); n > 0 && s[n:] == "_to_date" { //fiscal_year_to_date
+		return s[:n], true
+	}
+	return "", false
+}
+
+//newRange returns the Range spanning units l to u relative to t, or ErrUnknownUnit
+func (p *Parser) newRange(dp string, l, u int, t *time.Time) (Range, error) {
 	loc := t.Location()
+	if p.Retail != nil {
+		switch dp {
+		case "year", "half", "quarter", "month":
+			return p.Retail.relative(dp, l, u, t), nil
+		}
+	}
+	if p.UseFiscal {
+		switch dp {
+		case "year", "half", "quarter":
+			return p.Fiscal.relative(dp, l, u, t), nil
+		}
+	}
 	switch dp {
+	case "fiscal_year", "fiscal_half", "fiscal_quarter":
+		return p.Fiscal.relative(dp[7:], l, u, t), nil
 	case "day":
-		return day(t.Year(), int(t.Month()), t.Day()+l, u-l+1, loc)
+		return day(t.Year(), int(t.Month()), t.Day()+l, u-l+1, loc), nil
 	case "hour":
-		return minute(t.Year(), int(t.Month()), t.Day(), t.Hour()+l, 0, (u-l+1)*60, loc)
+		return minute(t.Year(), int(t.Month()), t.Day(), t.Hour()+l, 0, (u-l+1)*60, loc), nil
 	case "min", "minute":
-		return minute(t.Year(), int(t.Month()), t.Day(), t.Hour(), t.Minute()+l, u-l+1, loc)
+		return minute(t.Year(), int(t.Month()), t.Day(), t.Hour(), t.Minute()+l, u-l+1, loc), nil
+	case "sec", "second":
+		return instant(t.Year(), int(t.Month()), t.Day(), t.Hour(), t.Minute(), t.Second()+l, 0, time.Duration(u-l+1)*time.Second, loc), nil
+	case "ms", "millisecond":
+		ms := t.Nanosecond()/int(time.Millisecond) + l
+		return instant(t.Year(), int(t.Month()), t.Day(), t.Hour(), t.Minute(), t.Second(), time.Duration(ms)*time.Millisecond, time.Duration(u-l+1)*time.Millisecond, loc), nil
 	case "month":
-		return month(t.Year(), int(t.Month()+time.Month(l)), u-l+1, loc)
+		return month(t.Year(), int(t.Month()+time.Month(l)), u-l+1, loc), nil
+	case "quarter":
+		m := (int(t.Month())-1)/3*3 + 1
+		return month(t.Year(), m+l*3, (u-l+1)*3, loc), nil
+	case "half":
+		m := (int(t.Month())-1)/6*6 + 1
+		return month(t.Year(), m+l*6, (u-l+1)*6, loc), nil
 	case "year":
-		return year(t.Year()+l, u-l+1, loc)
+		return year(t.Year()+l, u-l+1, loc), nil
 	case "week":
-		d := t.Weekday() - firstWeekday
+		d := t.Weekday() - p.FirstWeekday
 		if d < 0 {
 			d += 7
 		}
-		return day(t.Year(), int(t.Month()), -int(d)+t.Day()+l*7, (u-l+1)*7, loc)
+		return day(t.Year(), int(t.Month()), -int(d)+t.Day()+l*7, (u-l+1)*7, loc), nil
+	}
+	return Range{}, ErrUnknownUnit
+}
+
+//unitMonths returns the number of months in a year, half, quarter or month
+func unitMonths(dp string) int {
+	switch dp {
+	case "year":
+		return 12
+	case "half":
+		return 6
+	case "quarter":
+		return 3
+	}
+	return 1
+}
+
+var dayNames = [...]string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+
+//weekday returns the day of the week named dp
+func weekday(dp string) (time.Weekday, bool) {
+	for i, s := range dayNames {
+		if s == dp {
+			return time.Weekday(i), true
+		}
+	}
+	return 0, false
+}
+
+//days returns the first day and number of days of the weekday, weekend or workweek named dp
+func days(dp string) (time.Weekday, int, bool) {
+	switch dp {
+	case "weekend":
+		return time.Saturday, 2, true //Saturday and the Sunday after
+	case "workweek":
+		return time.Monday, 5, true
+	}
+	wd, ok := weekday(dp)
+	return wd, 1, ok
+}
+
+//weekday returns n days from day wd of the week l weeks from t's,
+//or when latest is set from the last wd before t's day and l weeks on
+func (p *Parser) weekday(wd time.Weekday, n int, latest bool, l int, t *time.Time) Range {
+	if latest {
+		d := int(t.Weekday()-wd+6)%7 + 1 //1-7 days back
+		return day(t.Year(), int(t.Month()), t.Day()-d+l*7, n, t.Location())
 	}
-	return err()
+	d := int(t.Weekday()-p.FirstWeekday+7) % 7 //days into the week, as per newRange
+	o := int(wd-p.FirstWeekday+7) % 7
+	return day(t.Year(), int(t.Month()), t.Day()-d+o+l*7, n, t.Location())
 }
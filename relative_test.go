@@ -1,6 +1,7 @@
 package timeframe
 
 import (
+	"errors"
 	"testing"
 	"time"
 )
@@ -38,6 +39,32 @@ func TestBadRelative(t *testing.T) {
 		"last_hour",
 		"last_min",
 		"last_minute",
+		"last_sec",
+		"last_ms",
+		"prev_secs", //s suffix, except on ms
+		"prev_msecs",
+		"last_1000_ms",   //beyond MaxOffset
+		"prev_2_mondays", //multiple weekdays are not a single Range
+		"last_2_fridays",
+		"this_3_sundays",
+		"next_mon", //only full names
+		"prev_mondays",
+		"this_funday",
+		"next_2_weekends", //see RelativeSet
+		"prev_3_workweeks",
+		"last_weekend",
+		"prev_weekends",
+		"xtd", //to-date
+		"mtd_",
+		"_to_date",
+		"to_date",
+		"weekend_to_date",
+		"days_to_date",
+		"rolling_0_days", //rolling
+		"past_days",
+		"past_2_weekends",
+		"rolling_3_mondays",
+		"rolling_1000_days",
 	}
 	for _, p := range patterns {
 		t.Run(p, func(t *testing.T) {
@@ -79,6 +106,15 @@ func TestRelative(t *testing.T) {
 	}{
 		{"today", "2017-04-16", "2017-04-17"},
 		{"this_week", "2017-04-10", "2017-04-17"}, //boosts coverage
+		{"this_quarter", "2017-04-01", "2017-07-01"},
+		{"prev_quarter", "2017-01-01", "2017-04-01"},
+		{"2_quarters_ago", "2016-10-01", "2017-01-01"},
+		{"last_2_quarters", "2017-01-01", "2017-07-01"},
+		{"next_3_quarters", "2017-07-01", "2018-04-01"},
+		{"this_half", "2017-01-01", "2017-07-01"},
+		{"next_half", "2017-07-01", "2018-01-01"},
+		{"prev_2_halves", "2016-01-01", "2017-01-01"},
+		{"3_halves_ahead", "2018-07-01", "2019-01-01"},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.pat, func(t *testing.T) {
@@ -96,6 +132,282 @@ func TestRelative(t *testing.T) {
 	}
 }
 
+func TestRelativeClock(t *testing.T) {
+	const format = "2006-01-02 15:04:05.000"
+	loc, _ := time.LoadLocation("Europe/London")
+	rel := time.Date(2017, 03, 18, 22, 50, 42, 123456789, loc)
+	testCases := []struct {
+		pat   string
+		lower string
+		upper string
+	}{
+		{"this_min", "2017-03-18 22:50:00.000", "2017-03-18 22:51:00.000"},
+		{"this_sec", "2017-03-18 22:50:42.000", "2017-03-18 22:50:43.000"},
+		{"prev_second", "2017-03-18 22:50:41.000", "2017-03-18 22:50:42.000"},
+		{"last_30_seconds", "2017-03-18 22:50:13.000", "2017-03-18 22:50:43.000"},
+		{"prev_10_secs", "2017-03-18 22:50:32.000", "2017-03-18 22:50:42.000"},
+		{"5_seconds_ago", "2017-03-18 22:50:37.000", "2017-03-18 22:50:38.000"},
+		{"next_999_secs", "2017-03-18 22:50:43.000", "2017-03-18 23:07:22.000"},
+		{"0_sec_ahead", "2017-03-18 22:50:42.000", "2017-03-18 22:50:43.000"},
+		{"this_ms", "2017-03-18 22:50:42.123", "2017-03-18 22:50:42.124"},
+		{"next_millisecond", "2017-03-18 22:50:42.124", "2017-03-18 22:50:42.125"},
+		{"last_500_ms", "2017-03-18 22:50:41.624", "2017-03-18 22:50:42.124"},
+		{"200_milliseconds_ago", "2017-03-18 22:50:41.923", "2017-03-18 22:50:41.924"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pat, func(t *testing.T) {
+			r, err := Relative(tc.pat, &rel)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if inc, _ := time.ParseInLocation(format, tc.lower, loc); inc != r.LowerInc {
+				t.Errorf("L %s %s", inc, r.LowerInc)
+			}
+			if exc, _ := time.ParseInLocation(format, tc.upper, loc); exc != r.UpperExc {
+				t.Errorf("U %s %s", exc, r.UpperExc)
+			}
+		})
+	}
+}
+
+func TestRelativeWeekday(t *testing.T) {
+	const format = "2006-01-02"
+	loc, _ := time.LoadLocation("Europe/London")
+	rel := time.Date(2017, 03, 15, 22, 50, 0, 0, loc) //a Wednesday
+	sun := NewParser()
+	sun.FirstWeekday = time.Sunday
+	testCases := []struct {
+		p   *Parser
+		pat string
+		day string
+	}{
+		{std, "this_monday", "2017-03-13"},
+		{std, "this_wednesday", "2017-03-15"},
+		{std, "this_sunday", "2017-03-19"},
+		{std, "prev_monday", "2017-03-06"},
+		{std, "previous_sunday", "2017-03-12"},
+		{std, "next_friday", "2017-03-24"},
+		{std, "next_monday", "2017-03-20"},
+		{std, "2_tuesdays_ago", "2017-02-28"},
+		{std, "0_thursdays_ago", "2017-03-16"},
+		{std, "1_saturday_ahead", "2017-03-25"},
+		{std, "last_monday", "2017-03-13"},
+		{std, "last_tuesday", "2017-03-14"},
+		{std, "last_wednesday", "2017-03-08"}, //not today
+		{std, "last_thursday", "2017-03-09"},
+		{std, "last_1_thursday", "2017-03-09"},
+		{sun, "this_sunday", "2017-03-12"},
+		{sun, "this_saturday", "2017-03-18"},
+		{sun, "prev_sunday", "2017-03-05"},
+		{sun, "next_monday", "2017-03-20"},
+		{sun, "last_sunday", "2017-03-12"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pat, func(t *testing.T) {
+			r, err := tc.p.Relative(tc.pat, &rel)
+			if err != nil {
+				t.Fatal(err)
+			}
+			inc, _ := time.ParseInLocation(format, tc.day, loc)
+			if inc != r.LowerInc || inc.AddDate(0, 0, 1) != r.UpperExc {
+				t.Error(r)
+			}
+		})
+	}
+}
+
+func TestRelativeWeekend(t *testing.T) {
+	const format = "2006-01-02"
+	loc, _ := time.LoadLocation("Europe/London")
+	rel := time.Date(2017, 03, 15, 22, 50, 0, 0, loc) //a Wednesday
+	sun := NewParser()
+	sun.FirstWeekday = time.Sunday
+	testCases := []struct {
+		p     *Parser
+		pat   string
+		lower string
+		upper string
+	}{
+		{std, "this_weekend", "2017-03-18", "2017-03-20"},
+		{std, "prev_weekend", "2017-03-11", "2017-03-13"},
+		{std, "next_weekend", "2017-03-25", "2017-03-27"},
+		{std, "2_weekends_ago", "2017-03-04", "2017-03-06"},
+		{std, "last_1_weekend", "2017-03-18", "2017-03-20"},
+		{std, "this_workweek", "2017-03-13", "2017-03-18"},
+		{std, "prev_workweek", "2017-03-06", "2017-03-11"},
+		{std, "1_workweek_ahead", "2017-03-20", "2017-03-25"},
+		{sun, "this_weekend", "2017-03-18", "2017-03-20"}, //Saturday of the week, Sunday of the next
+		{sun, "this_workweek", "2017-03-13", "2017-03-18"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pat, func(t *testing.T) {
+			r, err := tc.p.Relative(tc.pat, &rel)
+			if err != nil {
+				t.Fatal(err)
+			}
+			inc, _ := time.ParseInLocation(format, tc.lower, loc)
+			exc, _ := time.ParseInLocation(format, tc.upper, loc)
+			if inc != r.LowerInc || exc != r.UpperExc {
+				t.Error(r)
+			}
+		})
+	}
+}
+
+func TestRelativeSet(t *testing.T) {
+	const format = "2006-01-02"
+	loc, _ := time.LoadLocation("Europe/London")
+	rel := time.Date(2017, 03, 15, 22, 50, 0, 0, loc) //a Wednesday
+	d := func(s string) time.Time {
+		t, _ := time.ParseInLocation(format, s, loc)
+		return t
+	}
+	testCases := []struct {
+		pat string
+		exp []Range
+	}{
+		{"next_2_weekends", []Range{{d("2017-03-25"), d("2017-03-27")}, {d("2017-04-01"), d("2017-04-03")}}},
+		{"prev_2_workweeks", []Range{{d("2017-02-27"), d("2017-03-04")}, {d("2017-03-06"), d("2017-03-11")}}},
+		{"this_2_fridays", []Range{{d("2017-03-17"), d("2017-03-18")}, {d("2017-03-24"), d("2017-03-25")}}},
+		{"last_2_mondays", []Range{{d("2017-03-06"), d("2017-03-07")}, {d("2017-03-13"), d("2017-03-14")}}},
+		{"this_weekend", []Range{{d("2017-03-18"), d("2017-03-20")}}},
+		{"this_month", []Range{{d("2017-03-01"), d("2017-04-01")}}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pat, func(t *testing.T) {
+			rs, err := RelativeSet(tc.pat, &rel)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !rs.Equal(NewRangeSet(tc.exp...)) {
+				t.Error(rs.Ranges())
+			}
+		})
+	}
+	for _, pat := range []string{"prev_2_funday", "past_2_mondays", "rolling_weekend", "past_3_workweeks"} {
+		if _, err := RelativeSet(pat, &rel); !errors.Is(err, ErrUnknownUnit) {
+			t.Error(pat, err)
+		}
+	}
+}
+
+func TestRelativeToDate(t *testing.T) {
+	const format = "2006-01-02T15:04"
+	loc, _ := time.LoadLocation("Europe/London")
+	rel := time.Date(2017, 03, 15, 22, 50, 30, 5e8, loc)
+	min := NewParser()
+	min.ToDatePrecision = time.Minute
+	testCases := []struct {
+		p     *Parser
+		pat   string
+		lower string
+	}{
+		{std, "today_so_far", "2017-03-15T00:00"},
+		{std, "day_to_date", "2017-03-15T00:00"},
+		{std, "hour_to_date", "2017-03-15T22:00"},
+		{std, "wtd", "2017-03-13T00:00"},
+		{std, "week_to_date", "2017-03-13T00:00"},
+		{std, "mtd", "2017-03-01T00:00"},
+		{std, "month_to_date", "2017-03-01T00:00"},
+		{std, "qtd", "2017-01-01T00:00"},
+		{std, "half_to_date", "2017-01-01T00:00"},
+		{std, "ytd", "2017-01-01T00:00"},
+		{std, "fiscal_year_to_date", "2017-01-01T00:00"},
+		{min, "mtd", "2017-03-01T00:00"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pat, func(t *testing.T) {
+			r, err := tc.p.Relative(tc.pat, &rel)
+			if err != nil {
+				t.Fatal(err)
+			}
+			exc := rel
+			if tc.p.ToDatePrecision != 0 {
+				exc, _ = time.ParseInLocation(format, "2017-03-15T22:50", loc)
+			}
+			if inc, _ := time.ParseInLocation(format, tc.lower, loc); inc != r.LowerInc || exc != r.UpperExc {
+				t.Error(r)
+			}
+		})
+	}
+}
+
+func TestRelativeRolling(t *testing.T) {
+	const format = "2006-01-02T15:04:05.000"
+	loc, _ := time.LoadLocation("Europe/London")
+	rel := time.Date(2017, 03, 31, 22, 50, 30, 5e8, loc)
+	testCases := []struct {
+		pat   string
+		lower string
+	}{
+		{"rolling_24_hours", "2017-03-30T22:50:30.500"},
+		{"past_7_days", "2017-03-24T22:50:30.500"},
+		{"past_day", "2017-03-30T22:50:30.500"},
+		{"rolling_2_days", "2017-03-29T22:50:30.500"},
+		{"past_26_hours", "2017-03-30T20:50:30.500"},
+		{"past_1_week", "2017-03-24T22:50:30.500"},
+		{"rolling_90_mins", "2017-03-31T21:20:30.500"},
+		{"past_10_secs", "2017-03-31T22:50:20.500"},
+		{"past_600_ms", "2017-03-31T22:50:29.900"},
+		{"past_month", "2017-03-03T22:50:30.500"}, //AddDate normalises 31 February
+		{"rolling_1_quarter", "2016-12-31T22:50:30.500"},
+		{"past_2_halves", "2016-03-31T22:50:30.500"},
+		{"past_year", "2016-03-31T22:50:30.500"},
+		{"rolling_fiscal_year", "2016-03-31T22:50:30.500"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pat, func(t *testing.T) {
+			r, err := Relative(tc.pat, &rel)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if inc, _ := time.ParseInLocation(format, tc.lower, loc); inc != r.LowerInc || rel != r.UpperExc {
+				t.Error(r)
+			}
+		})
+	}
+}
+
+func TestRelativeToDateZone(t *testing.T) {
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	rel := time.Date(2017, 03, 18, 8, 0, 0, 0, tokyo) //still the 17th in UTC
+	testCases := []struct {
+		precision time.Duration
+		exc       time.Time
+	}{
+		{24 * time.Hour, time.Date(2017, 03, 18, 0, 0, 0, 0, tokyo)},
+		{6 * time.Hour, time.Date(2017, 03, 18, 6, 0, 0, 0, tokyo)},
+		{time.Hour, time.Date(2017, 03, 18, 8, 0, 0, 0, tokyo)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.precision.String(), func(t *testing.T) {
+			p := NewParser()
+			p.ToDatePrecision = tc.precision
+			r, err := p.Relative("today_so_far", &rel)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.LowerInc != time.Date(2017, 03, 18, 0, 0, 0, 0, tokyo) || r.UpperExc != tc.exc {
+				t.Error(r)
+			}
+		})
+	}
+}
+
+func TestRelativeMaxOffset(t *testing.T) {
+	rel := time.Date(2017, 03, 18, 22, 50, 0, 0, time.UTC)
+	p := NewParser()
+	p.MaxOffset = 100000
+	for _, s := range []string{"previous_99999_fiscal_quarters", "previous_1000_minutes", "rolling_100000_milliseconds"} {
+		if _, err := p.Relative(s, &rel); err != nil {
+			t.Error(err)
+		}
+	}
+	if _, err := p.Relative("previous_100001_fiscal_quarters", &rel); !errors.Is(err, ErrOffsetOutOfRange) {
+		t.Error(err)
+	}
+}
+
 func TestRelativeAlias(t *testing.T) {
 	testCases := []struct {
 		pat      string
@@ -125,6 +437,12 @@ func TestRelativeAlias(t *testing.T) {
 		{"previous_hour", "hour", -1},
 		{"this_hour", "hour", 0},
 		{"next_hour", "hour", +1},
+		{"prev_quarter", "quarter", -1},
+		{"this_quarter", "quarter", 0},
+		{"next_quarter", "quarter", +1},
+		{"prev_half", "half", -1},
+		{"this_half", "half", 0},
+		{"next_half", "half", +1},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.pat, func(t *testing.T) {
@@ -181,6 +499,10 @@ func TestRelativeSlice(t *testing.T) {
 		{"this_111_weeks", "week", 0, 110},
 		{"this_11_months", "month", 0, 10},
 		{"this_1_day", "day", 0, 0},
+
+		{"previous_999_quarters", "quarter", -999, -1},
+		{"last_4_quarters", "quarter", -3, 0},
+		{"2_halves_ago", "half", -2, -2},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.pat, func(t *testing.T) {
@@ -220,6 +542,18 @@ func expected(datepart string, lower int, upper int) Range {
 			LowerInc: month.AddDate(0, lower, 0),
 			UpperExc: month.AddDate(0, upper+1, 0),
 		}
+	case "quarter":
+		quarter := time.Date(today.Year(), (today.Month()-1)/3*3+1, 1, 0, 0, 0, 0, time.Local)
+		return Range{
+			LowerInc: quarter.AddDate(0, lower*3, 0),
+			UpperExc: quarter.AddDate(0, upper*3+3, 0),
+		}
+	case "half":
+		half := time.Date(today.Year(), (today.Month()-1)/6*6+1, 1, 0, 0, 0, 0, time.Local)
+		return Range{
+			LowerInc: half.AddDate(0, lower*6, 0),
+			UpperExc: half.AddDate(0, upper*6+6, 0),
+		}
 	case "year":
 		year := time.Date(today.Year(), 1, 1, 0, 0, 0, 0, time.Local)
 		return Range{
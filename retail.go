@@ -0,0 +1,111 @@
+package timeframe
+
+import "time"
+
+//RetailCalendar describes a 52/53 week retail calendar, like the NRF 4-5-4, in which months are whole weeks
+//
+//Retail years are named after the calendar year in which most of their days fall, so with EndMonth set
+//to January the year 2017 ends in January 2018, and month 1 is the first month of the retail year
+type RetailCalendar struct {
+	Pattern       RetailPattern //weeks in each month of a quarter
+	EndMonth      time.Month    //the year ends on the EndWeekday nearest the end of this month
+	EndWeekday    time.Weekday  //last day of the retail week
+	Last          bool          //end on the last EndWeekday within EndMonth, rather than the nearest
+	LeapWeekMonth int           //month 1-12 that gains the 53rd week when there is one, 0 means the last
+}
+
+//RetailPattern holds the number of weeks in each month of a retail quarter
+type RetailPattern [3]int
+
+//Common patterns of weeks per month
+var (
+	Retail445 = RetailPattern{4, 4, 5}
+	Retail454 = RetailPattern{4, 5, 4}
+	Retail544 = RetailPattern{5, 4, 4}
+)
+
+//NewNRFCalendar returns the National Retail Federation's 4-5-4 calendar, whose years end on the
+//Saturday nearest the end of January
+func NewNRFCalendar() *RetailCalendar {
+	return &RetailCalendar{
+		Pattern:       Retail454,
+		EndMonth:      time.January,
+		EndWeekday:    time.Saturday,
+		LeapWeekMonth: 12,
+	}
+}
+
+//yearEnd returns midnight UTC on the day after retail year y ends
+func (c *RetailCalendar) yearEnd(y int) time.Time {
+	m := c.EndMonth
+	if m == 0 {
+		m = time.January
+	}
+	if m < time.July {
+		y++ //most of the year falls before the end month
+	}
+	last := time.Date(y, m+1, 0, 0, 0, 0, 0, time.UTC)
+	back := int(last.Weekday()-c.EndWeekday+7) % 7
+	if !c.Last && back > 3 {
+		back -= 7 //the following one is nearer
+	}
+	return last.AddDate(0, 0, 1-back)
+}
+
+//monthStart returns midnight UTC on the first day of month i, counting months from year 0
+func (c *RetailCalendar) monthStart(i int) time.Time {
+	y, m := i/12, i%12
+	if m < 0 {
+		y, m = y-1, m+12
+	}
+	start, end := c.yearEnd(y-1), c.yearEnd(y)
+	leap := c.LeapWeekMonth
+	if leap == 0 {
+		leap = 12
+	}
+	if end.Sub(start) == 52*7*24*time.Hour {
+		leap = -1 //no 53rd week this year
+	}
+	w := 0
+	for k := 0; k < m; k++ {
+		w += c.Pattern[k%3]
+		if k+1 == leap {
+			w++
+		}
+	}
+	return start.AddDate(0, 0, w*7)
+}
+
+//months returns l months starting with month i, counting months from year 0
+func (c *RetailCalendar) months(i, l int, loc *time.Location) Range {
+	a, b := c.monthStart(i), c.monthStart(i+l)
+	return Range{
+		LowerInc: time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, loc),
+		UpperExc: time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, loc),
+	}
+}
+
+//locate returns the month containing t, counting months from year 0
+func (c *RetailCalendar) locate(t time.Time) int {
+	d := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	y := t.Year()
+	for d.Before(c.yearEnd(y - 1)) {
+		y--
+	}
+	for !d.Before(c.yearEnd(y)) {
+		y++
+	}
+	i := y * 12
+	for !d.Before(c.monthStart(i + 1)) {
+		i++
+	}
+	return i
+}
+
+//relative returns the Range spanning retail years, halves, quarters or months l to u relative to t
+func (c *RetailCalendar) relative(dp string, l, u int, t *time.Time) Range {
+	n := unitMonths(dp)
+	i := c.locate(*t)
+	i -= (i%n + n) % n //back to the start of the current period
+	return c.months(i+l*n, (u-l+1)*n, t.Location())
+}
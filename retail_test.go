@@ -0,0 +1,94 @@
+package timeframe
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetail(t *testing.T) {
+	const format = "2006-01-02"
+	loc, _ := time.LoadLocation("America/New_York")
+	rel := time.Date(2017, 03, 18, 22, 50, 0, 0, loc)
+	nrf := NewParser()
+	nrf.Retail = NewNRFCalendar()
+	first := NewParser() //53rd week in the first month instead
+	first.Retail = NewNRFCalendar()
+	first.Retail.LeapWeekMonth = 1
+	dec := NewParser() //4-4-5 ending on the last Sunday of December
+	dec.Retail = &RetailCalendar{Pattern: Retail445, EndMonth: time.December, EndWeekday: time.Sunday, Last: true}
+	testCases := []struct {
+		p     *Parser
+		pat   string
+		lower string
+		upper string
+	}{
+		{nrf, "2016", "2016-01-31", "2017-01-29"}, //52 weeks
+		{nrf, "2017", "2017-01-29", "2018-02-04"}, //53 weeks
+		{nrf, "2017-01", "2017-01-29", "2017-02-26"},
+		{nrf, "201702", "2017-02-26", "2017-04-02"},
+		{nrf, "2017-03", "2017-04-02", "2017-04-30"},
+		{nrf, "2017-12", "2017-12-31", "2018-02-04"}, //gains the 53rd week
+		{nrf, "2016-12", "2017-01-01", "2017-01-29"},
+		{nrf, "2017-Q1", "2017-01-29", "2017-04-30"},
+		{nrf, "2017-Q4", "2017-10-29", "2018-02-04"},
+		{nrf, "2017-H2", "2017-07-30", "2018-02-04"},
+		{nrf, "2017-03-18", "2017-03-18", "2017-03-19"}, //days are unaffected
+		{nrf, "2017-W11", "2017-03-13", "2017-03-20"},   //as are ISO weeks
+		{nrf, "FY2017", "2017-01-01", "2018-01-01"},     //as are fiscal years
+		{nrf, "this_month", "2017-02-26", "2017-04-02"},
+		{nrf, "prev_month", "2017-01-29", "2017-02-26"},
+		{nrf, "2_months_ago", "2017-01-01", "2017-01-29"},
+		{nrf, "this_quarter", "2017-01-29", "2017-04-30"},
+		{nrf, "next_quarter", "2017-04-30", "2017-07-30"},
+		{nrf, "this_half", "2017-01-29", "2017-07-30"},
+		{nrf, "this_year", "2017-01-29", "2018-02-04"},
+		{nrf, "prev_year", "2016-01-31", "2017-01-29"},
+		{nrf, "last_2_years", "2016-01-31", "2018-02-04"},
+		{nrf, "today", "2017-03-18", "2017-03-19"},
+		{first, "2017-01", "2017-01-29", "2017-03-05"},
+		{first, "2017-12", "2018-01-07", "2018-02-04"},
+		{first, "2016-01", "2016-01-31", "2016-02-28"},
+		{dec, "2016", "2015-12-28", "2016-12-26"},
+		{dec, "2017", "2016-12-26", "2018-01-01"}, //53 weeks
+		{dec, "2017-Q1", "2016-12-26", "2017-03-27"},
+		{dec, "2017-03", "2017-02-20", "2017-03-27"},
+		{dec, "this_month", "2017-02-20", "2017-03-27"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pat, func(t *testing.T) {
+			var r Range
+			var err error
+			if isDigit(tc.pat[3]) {
+				r, err = tc.p.Absolute(tc.pat, loc)
+			} else {
+				r, err = tc.p.Relative(tc.pat, &rel)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if inc, _ := time.ParseInLocation(format, tc.lower, loc); inc != r.LowerInc {
+				t.Errorf("L %s %s", inc, r.LowerInc)
+			}
+			if exc, _ := time.ParseInLocation(format, tc.upper, loc); exc != r.UpperExc {
+				t.Errorf("U %s %s", exc, r.UpperExc)
+			}
+		})
+	}
+}
+
+//TestRetailContiguous verifies retail months tile the years without gaps, in whole weeks
+func TestRetailContiguous(t *testing.T) {
+	c := NewNRFCalendar()
+	prev := c.months(1990*12, 1, time.UTC)
+	for i := 1990*12 + 1; i < 2050*12; i++ {
+		r := c.months(i, 1, time.UTC)
+		weeks := r.Duration() / (7 * 24 * time.Hour)
+		if r.LowerInc != prev.UpperExc || weeks < 4 || weeks > 6 || r.Duration()%(7*24*time.Hour) != 0 {
+			t.Fatal(i/12, i%12+1, r)
+		}
+		if c.locate(r.LowerInc) != i || c.locate(r.UpperExc.Add(-time.Hour)) != i {
+			t.Fatal("locate", i/12, i%12+1)
+		}
+		prev = r
+	}
+}
@@ -0,0 +1,113 @@
+package timeframe
+
+//scanner walks a token from left to right, remembering only the first failure it meets
+type scanner struct {
+	s      string
+	i      int
+	reason error
+	fail   int
+}
+
+func (sc *scanner) failAt(i int, reason error) {
+	if sc.reason == nil {
+		sc.reason, sc.fail = reason, i
+	}
+}
+
+func (sc *scanner) end() bool {
+	return sc.reason == nil && sc.i == len(sc.s)
+}
+
+//skip consumes c if it is next
+func (sc *scanner) skip(c byte) bool {
+	if sc.reason == nil && sc.i < len(sc.s) && sc.s[sc.i] == c {
+		sc.i++
+		return true
+	}
+	return false
+}
+
+//expect consumes c, failing if something else is next
+func (sc *scanner) expect(c byte) {
+	if !sc.skip(c) {
+		sc.failAt(sc.i, ErrBadSeparator)
+	}
+}
+
+//sep consumes c in the extended format, the basic format has no separators
+func (sc *scanner) sep(ext bool, c byte) {
+	if ext {
+		sc.expect(c)
+	}
+}
+
+//digits counts the run of digits that is next, without consuming them
+func (sc *scanner) digits() int {
+	n := 0
+	for sc.i+n < len(sc.s) && isDigit(sc.s[sc.i+n]) {
+		n++
+	}
+	return n
+}
+
+//num consumes n digits, failing with reason unless their value lies within min-max
+func (sc *scanner) num(n, min, max int, reason error) int {
+	if sc.reason != nil {
+		return -1
+	}
+	if sc.digits() < n {
+		sc.failAt(sc.i, ErrNotRecognised)
+		return -1
+	}
+	v := 0
+	for _, c := range []byte(sc.s[sc.i : sc.i+n]) {
+		v = v*10 + int(c-0x30 /*0*/)
+	}
+	if v < min || v > max {
+		sc.failAt(sc.i, reason)
+		return -1
+	}
+	sc.i += n
+	return v
+}
+
+//run consumes a run of between 1 and max digits, failing with reason if it is any longer
+func (sc *scanner) run(max int, reason error) int {
+	n := sc.digits()
+	if n > max {
+		sc.failAt(sc.i, reason)
+		return -1
+	}
+	if n == 0 {
+		n = 1 //so that num reports the missing digit
+	}
+	return sc.num(n, 0, int(^uint(0)>>1), reason)
+}
+
+//finish returns r, or a ParseError about the token s if the scanner failed or stopped short
+func (sc *scanner) finish(s string, r Range) (Range, error) {
+	if sc.reason == nil && sc.i < len(sc.s) {
+		sc.failAt(sc.i, ErrNotRecognised) //trailing characters
+	}
+	if e := sc.err(s); e != nil {
+		return Range{}, e
+	}
+	return r, nil
+}
+
+//err returns a ParseError about the token s if the scanner has failed so far
+func (sc *scanner) err(s string) error {
+	if sc.reason == nil {
+		return nil
+	}
+	return &ParseError{
+		Input:  s,
+		Offset: sc.fail,
+		Kind:   AbsoluteToken,
+		Reason: sc.reason,
+	}
+}
+
+func isDigit(c byte) bool {
+	return c >= 0x30 /*0*/ && c <= 0x39 /*9*/
+}
@@ -1,73 +1,205 @@
 package timeframe
 
 import (
-	"errors"
+	"strings"
 	"time"
 )
 
-const (
-	maxOffset    = 999         //arbitrary
-	firstWeekday = time.Monday //as per ISO 8601
-	allowYYYYMM  = true        //disallowed by the ISO 8601 (to avoid confusion with YYMMDD)
-	minYear      = 0001        //years prior to 1583 are not automatically allowed
-	maxYear      = 9999
-)
+const isoWeekday = time.Monday //as per ISO 8601, week dates ignore Parser.FirstWeekday
+
+//Parser holds the options that control how tokens are recognised
+//
+//The zero value is not useful, use NewParser to obtain a Parser with the package defaults
+type Parser struct {
+	FirstWeekday    time.Weekday    //start of the week for relative tokens like this_week
+	MaxOffset       int             //largest n accepted by relative tokens like n_days_ago
+	AllowYYYYMM     bool            //disallowed by the ISO 8601 (to avoid confusion with YYMMDD)
+	MinYear         int             //smallest year accepted by absolute tokens
+	MaxYear         int             //largest year accepted by absolute tokens
+	YearDigits      int             //when set, also accept signed years of exactly that many digits like +12017 or -0044, regardless of MinYear and MaxYear
+	Location        *time.Location  //used when no location is passed in, nil means time.Local
+	Clock           Clock           //reference time for relative tokens, nil means the system clock
+	Fiscal          FiscalYear      //calendar of tokens like FY2017 and this_fiscal_year
+	UseFiscal       bool            //resolve plain year, half and quarter tokens like 2017-Q1 and this_year against Fiscal too
+	Retail          *RetailCalendar //when set, resolve plain year, half, quarter and month tokens against it instead
+	ToDatePrecision time.Duration   //truncates the end of to-date tokens like mtd on the wall clock; 0 means none
+}
+
+//Clock provides the reference time that relative tokens are resolved against
+type Clock interface {
+	Now() time.Time
+}
+
+//ClockFunc adapts an ordinary function, such as time.Now, to the Clock interface
+type ClockFunc func() time.Time
+
+//Now returns f()
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+//NewParser returns a Parser configured with the package defaults
+func NewParser() *Parser {
+	return &Parser{
+		FirstWeekday: time.Monday, //as per ISO 8601
+		MaxOffset:    999,         //arbitrary
+		AllowYYYYMM:  true,
+		MinYear:      0001, //years prior to 1583 are not automatically allowed
+		MaxYear:      9999,
+		Fiscal:       FiscalYear{Month: time.January, Day: 1},
+	}
+}
+
+//std backs the package level functions
+var std = NewParser()
 
 //Expand parses a token like 3_days_ago and returns the Range it represents
 func Expand(s string, loc *time.Location) (Range, error) {
-	if len(s) < 4 { //shortest tokens are "2017" and "today" respectively
-		return err()
+	return std.Expand(s, loc)
+}
+
+//Expand parses a token like 3_days_ago and returns the Range it represents
+//
+//A token may end in a zone annotation like 2017-03-18[Europe/London] or today@Asia/Tokyo, which takes precedence
+//over loc, so today@Asia/Tokyo is the day in Tokyo. An explicit offset like the Z of 2017-03-18T22:50Z[Asia/Tokyo]
+//takes precedence over the annotation in turn
+//
+//A token may end in an offset like 2017-03-18+2d or this_month-1y, which moves the whole Range by that many units.
+//Offsets in years, quarters and months clamp both bounds to the end of a shorter month, an end at midnight being
+//clamped as the end of the day before it, so 2017-01-31+1M and 2017-01-29/2017-01-31+1M are both 28 February,
+//while this_month+1M is always the whole of next month
+//
+//Two tokens joined by .. like 2017-03..2017-05 or prev_week..today give the Range from the start of the first
+//to the end of the second, which must start no earlier than the first ends
+func (p *Parser) Expand(s string, loc *time.Location) (Range, error) {
+	loc = p.location(loc)
+	if k := strings.Index(s, ".."); k != -1 {
+		return p.join(s, k, loc)
+	}
+	return p.expand(s, loc, nil)
+}
+
+//expand parses the single token s, resolving relative tokens against t or the Parser's Clock when t is nil
+func (p *Parser) expand(s string, loc *time.Location, t *time.Time) (Range, error) {
+	if k, i := annotation(s); k != -1 { //2017-03-18[Europe/London], today@Asia/Tokyo
+		return p.annotated(s, k, i, t)
+	}
+	return p.token(s, loc, t)
+}
+
+//token parses the single token s, without any zone annotation
+func (p *Parser) token(s string, loc *time.Location, t *time.Time) (Range, error) {
+	if k := offset(s); k != -1 { //this_month-1y
+		return p.shift(s, k, loc, t)
+	}
+	if len(s) < 3 { //shortest tokens are "2017" and "mtd" respectively
+		return fail(UnknownToken, s, 0, ErrNotRecognised)
+	}
+	if len(s) > 3 && isDigit(s[3]) || s[0] == 0x50 /*P*/ { //P2D/2017-03-18
+		return p.Absolute(s, loc)
+	}
+	if t == nil {
+		now := p.now().In(loc)
+		t = &now
+	}
+	return p.Relative(s, t)
+}
+
+//join parses the tokens either side of the .. at s[k], resolving both against the same reference time
+func (p *Parser) join(s string, k int, loc *time.Location) (Range, error) {
+	t := p.now().In(loc)
+	a, e := p.expand(s[:k], loc, &t)
+	if e != nil {
+		return Range{}, rebase(e, s, 0)
 	}
-	if loc == nil {
-		loc = time.Local
+	b, e := p.expand(s[k+2:], loc, &t)
+	if e != nil {
+		return Range{}, rebase(e, s, k+2)
 	}
-	if s[3] >= 0x30 /*0*/ && s[3] <= 0x39 /*9*/ {
-		return Absolute(s, loc)
+	switch {
+	case b.LowerInc.Before(a.LowerInc):
+		return fail(UnknownToken, s, k+2, ErrInvertedRange)
+	case b.LowerInc.Before(a.UpperExc):
+		return fail(UnknownToken, s, k+2, ErrOverlappingRange) //like this_month..today
 	}
-	t := time.Now().In(loc)
-	return Relative(s, &t)
+	return Range{
+		LowerInc: a.LowerInc,
+		UpperExc: b.UpperExc,
+	}, nil
 }
 
-func err() (Range, error) {
-	return Range{}, errors.New("Timeframe not recognised")
+//rebase makes a ParseError about part of s, starting at s[i], refer to the whole of s instead
+func rebase(e error, s string, i int) error {
+	if pe, ok := e.(*ParseError); ok {
+		pe.Input, pe.Offset = s, pe.Offset+i
+	}
+	return e
+}
+
+//now reads the Parser's Clock, falling back to the system clock
+func (p *Parser) now() time.Time {
+	if p.Clock != nil {
+		return p.Clock.Now()
+	}
+	return time.Now()
 }
 
-func year(y, l int, loc *time.Location) (Range, error) {
+//location returns loc, falling back to the Parser's default and then time.Local
+func (p *Parser) location(loc *time.Location) *time.Location {
+	if loc != nil {
+		return loc
+	}
+	if p.Location != nil {
+		return p.Location
+	}
+	return time.Local
+}
+
+func year(y, l int, loc *time.Location) Range {
 	year := time.Date(y, 1, 1, 0, 0, 0, 0, loc)
 	return Range{
 		LowerInc: year,
 		UpperExc: year.AddDate(l, 0, 0),
-	}, nil
+	}
 }
 
-func month(y, m, l int, loc *time.Location) (Range, error) {
+func month(y, m, l int, loc *time.Location) Range {
 	month := time.Date(y, time.Month(m), 1, 0, 0, 0, 0, loc)
 	return Range{
 		LowerInc: month,
 		UpperExc: month.AddDate(0, l, 0),
-	}, nil
+	}
 }
 
-func day(y, m, d, l int, loc *time.Location) (Range, error) {
+func day(y, m, d, l int, loc *time.Location) Range {
 	today := time.Date(y, time.Month(m), d, 0, 0, 0, 0, loc)
 	return Range{
 		LowerInc: today,
 		UpperExc: today.AddDate(0, 0, l),
-	}, nil
+	}
 }
 
-func minute(y, m, d, hh, mm, l int, loc *time.Location) (Range, error) {
+func minute(y, m, d, hh, mm, l int, loc *time.Location) Range {
 	t := time.Date(y, time.Month(m), d, hh, mm, 0, 0, loc)
 	return Range{
 		LowerInc: t,
 		UpperExc: t.Add(time.Minute * time.Duration(l)),
-	}, nil
+	}
 }
 
-//week finds start of week number w in year y, skips o days, then returns a range of l days
-func week(y, w, o, l int, loc *time.Location) (Range, error) {
+//instant returns a range of l starting o after second ss, o being a Duration as a fraction of an hour overflows a 32-bit int
+func instant(y, m, d, hh, mm, ss int, o, l time.Duration, loc *time.Location) Range {
+	t := time.Date(y, time.Month(m), d, hh, mm, ss, 0, loc).Add(o)
+	return Range{
+		LowerInc: t,
+		UpperExc: t.Add(l),
+	}
+}
+
+//week finds start of ISO week number w in year y, skips o days, then returns a range of l days
+func week(y, w, o, l int, loc *time.Location) Range {
 	jan4 := time.Date(y, 1, 4, 0, 0, 0, 0, loc)
-	d := jan4.Weekday() - firstWeekday
+	d := jan4.Weekday() - isoWeekday
 	if d < 0 {
 		d += 7
 	}
@@ -75,5 +207,5 @@ func week(y, w, o, l int, loc *time.Location) (Range, error) {
 	return Range{
 		LowerInc: week,
 		UpperExc: week.AddDate(0, 0, l),
-	}, nil
+	}
 }
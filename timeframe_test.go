@@ -2,6 +2,7 @@ package timeframe
 
 import (
 	"bytes"
+	"errors"
 	"math/rand"
 	"reflect"
 	"testing"
@@ -52,6 +53,95 @@ func TestBadExpand(t *testing.T) {
 	}
 }
 
+func TestExpandToDate(t *testing.T) {
+	p := NewParser()
+	p.Clock = ClockFunc(func() time.Time { return time.Date(2017, 03, 15, 22, 50, 0, 0, time.UTC) })
+	for _, s := range []string{"mtd", "ytd", "month_to_date"} {
+		r, err := p.Expand(s, time.UTC)
+		if err != nil || r.UpperExc != p.Clock.Now() {
+			t.Error(s, r, err)
+		}
+	}
+	for _, s := range []string{"td", "abc"} {
+		if _, err := p.Expand(s, time.UTC); err == nil {
+			t.Error(s)
+		}
+	}
+}
+
+func TestExpandJoin(t *testing.T) {
+	const format = "2006-01-02 15:04"
+	loc, _ := time.LoadLocation("Europe/London")
+	p := NewParser()
+	p.Clock = ClockFunc(func() time.Time { return time.Date(2017, 03, 15, 22, 50, 0, 0, loc) })
+	testCases := []struct {
+		pat   string
+		lower string
+		upper string
+	}{
+		{"2017-03..2017-05", "2017-03-01 00:00", "2017-06-01 00:00"},
+		{"prev_week..today", "2017-03-06 00:00", "2017-03-16 00:00"},
+		{"2017-03-01..today", "2017-03-01 00:00", "2017-03-16 00:00"},
+		{"2017-03-14..2017-03-15", "2017-03-14 00:00", "2017-03-16 00:00"},
+		{"2017-03-01/05..2017-03-10/P1D", "2017-03-01 00:00", "2017-03-11 00:00"},
+		{"mtd..tomorrow", "2017-03-01 00:00", "2017-03-17 00:00"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pat, func(t *testing.T) {
+			r, err := p.Expand(tc.pat, loc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if inc, _ := time.ParseInLocation(format, tc.lower, loc); inc != r.LowerInc {
+				t.Errorf("L %s %s", inc, r.LowerInc)
+			}
+			if exc, _ := time.ParseInLocation(format, tc.upper, loc); exc != r.UpperExc {
+				t.Errorf("U %s %s", exc, r.UpperExc)
+			}
+		})
+	}
+}
+
+func TestBadExpandJoin(t *testing.T) {
+	testCases := []struct {
+		pat    string
+		offset int
+		reason error
+	}{
+		{"..", 0, ErrNotRecognised},
+		{"2017..", 6, ErrNotRecognised},
+		{"..2017", 0, ErrNotRecognised},
+		{"2017-13..2017-05", 5, ErrMonthOutOfRange},
+		{"2017-03..2017-13", 14, ErrMonthOutOfRange},
+		{"2017-03..next_wek", 14, ErrUnknownUnit},
+		{"2017-05..2017-03", 9, ErrInvertedRange},
+		{"2017-03..2017-03", 9, ErrOverlappingRange},
+		{"2017..2017-03", 6, ErrOverlappingRange},
+		{"2017..2018..2019", 10, ErrBadSeparator},
+		{"today..yesterday", 7, ErrInvertedRange},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pat, func(t *testing.T) {
+			r, err := Expand(tc.pat, nil)
+			var pe *ParseError
+			if !r.IsZero() || !errors.As(err, &pe) {
+				t.Fatal(r, err)
+			}
+			if pe.Input != tc.pat || pe.Offset != tc.offset || pe.Reason != tc.reason {
+				t.Errorf("%q %d %v", pe.Input, pe.Offset, pe.Reason)
+			}
+		})
+	}
+}
+
+//TestInstantOffset verifies offsets beyond the range of a 32-bit int, as with GOARCH=386, are not truncated
+func TestInstantOffset(t *testing.T) {
+	r := instant(2017, 3, 18, 22, 0, 0, 30*time.Minute, 6*time.Minute, time.UTC) //1.8e12ns in
+	if r.LowerInc != time.Date(2017, 3, 18, 22, 30, 0, 0, time.UTC) || r.Duration() != 6*time.Minute {
+		t.Error(r)
+	}
+}
+
 func TestExpandNilLoc(t *testing.T) {
 	r, err := Expand("today", nil)
 	if err != nil || time.Local != r.LowerInc.Location() || time.Local != r.UpperExc.Location() {
@@ -97,3 +187,114 @@ func BenchmarkExpand(b *testing.B) {
 		})
 	}
 }
+
+func TestParserFirstWeekday(t *testing.T) {
+	const format = "2006-01-02"
+	loc, _ := time.LoadLocation("Europe/London")
+	rel := time.Date(2017, 04, 16, 12, 0, 0, 0, loc) //a Sunday
+	testCases := []struct {
+		first time.Weekday
+		lower string
+		upper string
+	}{
+		{time.Monday, "2017-04-10", "2017-04-17"},
+		{time.Sunday, "2017-04-16", "2017-04-23"},
+		{time.Saturday, "2017-04-15", "2017-04-22"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.first.String(), func(t *testing.T) {
+			p := NewParser()
+			p.FirstWeekday = tc.first
+			r, err := p.Relative("this_week", &rel)
+			if err != nil {
+				t.Fail()
+			}
+			if inc, _ := time.ParseInLocation(format, tc.lower, loc); inc != r.LowerInc {
+				t.Errorf("L %s %s", inc, r.LowerInc)
+			}
+			if exc, _ := time.ParseInLocation(format, tc.upper, loc); exc != r.UpperExc {
+				t.Errorf("U %s %s", exc, r.UpperExc)
+			}
+			r, err = p.Absolute("2017-W15", loc) //ISO weeks always start on Monday
+			if err != nil || r.LowerInc.Weekday() != time.Monday {
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestParserOptions(t *testing.T) {
+	p := NewParser()
+	p.AllowYYYYMM = false
+	p.MaxOffset = 99
+	p.MinYear = 1583
+	p.MaxYear = 2999
+	patterns := []string{"201703", "100_days_ago", "next_100_days", "1582", "1582-12-31", "3000-W01"}
+	for _, s := range patterns {
+		t.Run(s, func(t *testing.T) {
+			r, err := p.Expand(s, nil)
+			if !r.IsZero() || err == nil {
+				t.Fail()
+			}
+			if _, err := Expand(s, nil); err != nil {
+				t.Error("package defaults should be unaffected")
+			}
+		})
+	}
+}
+
+func TestParserLocation(t *testing.T) {
+	ny, _ := time.LoadLocation("America/New_York")
+	p := NewParser()
+	p.Location = ny
+	for _, s := range []string{"today", "2017-03-18"} {
+		t.Run(s, func(t *testing.T) {
+			r, err := p.Expand(s, nil)
+			if err != nil || ny != r.LowerInc.Location() || ny != r.UpperExc.Location() {
+				t.Fail()
+			}
+			r, err = p.Expand(s, time.UTC) //explicit location wins
+			if err != nil || time.UTC != r.LowerInc.Location() {
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestParserClock(t *testing.T) {
+	const format = "2006-01-02 15:04"
+	loc, _ := time.LoadLocation("Europe/London")
+	now := time.Date(2017, 03, 18, 22, 50, 42, 0, time.UTC)
+	p := NewParser()
+	p.Clock = ClockFunc(func() time.Time { return now })
+	p.Location = loc
+	testCases := []struct {
+		pat   string
+		lower string
+		upper string
+	}{
+		{"today", "2017-03-18 00:00", "2017-03-19 00:00"},
+		{"this_hour", "2017-03-18 22:00", "2017-03-18 23:00"},
+		{"prev_week", "2017-03-06 00:00", "2017-03-13 00:00"},
+		{"2_months_ago", "2017-01-01 00:00", "2017-02-01 00:00"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pat, func(t *testing.T) {
+			for _, f := range []func(string) (Range, error){
+				func(s string) (Range, error) { return p.Expand(s, nil) },
+				func(s string) (Range, error) { return p.Relative(s, nil) },
+			} {
+				r, err := f(tc.pat)
+				if err != nil {
+					t.Fail()
+				}
+				if inc, _ := time.ParseInLocation(format, tc.lower, loc); inc != r.LowerInc {
+					t.Errorf("L %s %s", inc, r.LowerInc)
+				}
+				if exc, _ := time.ParseInLocation(format, tc.upper, loc); exc != r.UpperExc {
+					t.Errorf("U %s %s", exc, r.UpperExc)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package timeframe
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+//annotation returns where the zone annotation at the end of s starts, like the [Europe/London] of
+//2017-03-18[Europe/London] or the @Asia/Tokyo of today@Asia/Tokyo, and where its name starts, or -1 if there is none
+func annotation(s string) (int, int) {
+	if strings.HasSuffix(s, "]") {
+		if k := strings.LastIndexByte(s, 0x5b /*[*/); k != -1 {
+			return k, k + 1
+		}
+		return -1, -1
+	}
+	if k := strings.LastIndexByte(s, 0x40 /*@*/); k != -1 {
+		return k, k + 1
+	}
+	return -1, -1
+}
+
+//annotated parses the token s[:k] in the zone named from s[i:], which takes precedence over loc
+//
+//Relative tokens are resolved against t as seen in that zone, so today@Asia/Tokyo is the day in Tokyo
+func (p *Parser) annotated(s string, k, i int, t *time.Time) (Range, error) {
+	name := s[i:]
+	if s[k] == 0x5b /*[*/ {
+		name = s[i : len(s)-1]
+	}
+	loc, e := loadZone(name)
+	if e != nil {
+		return fail(UnknownToken, s, i, ErrUnknownZone)
+	}
+	if t != nil {
+		tz := t.In(loc)
+		t = &tz
+	}
+	r, e := p.token(s[:k], loc, t)
+	if e != nil {
+		return Range{}, rebase(e, s, 0)
+	}
+	return r, nil
+}
+
+//zones caches the locations of annotations by name, as LoadLocation reads the zone database every time
+var zones sync.Map
+
+//loadZone returns the location with the given IANA name, like Europe/London
+func loadZone(name string) (*time.Location, error) {
+	if z, ok := zones.Load(name); ok {
+		return z.(*time.Location), nil
+	}
+	if name == "" || name == "Local" {
+		return nil, ErrUnknownZone //LoadLocation would give UTC or time.Local, neither of which is annotated
+	}
+	loc, e := time.LoadLocation(name)
+	if e != nil {
+		return nil, e
+	}
+	zones.Store(name, loc)
+	return loc, nil
+}
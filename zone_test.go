@@ -0,0 +1,74 @@
+package timeframe
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAnnotation(t *testing.T) {
+	const format = "2006-01-02 15:04"
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	london, _ := time.LoadLocation("Europe/London")
+	p := NewParser()
+	p.Clock = ClockFunc(func() time.Time { return time.Date(2017, 03, 15, 22, 50, 0, 0, london) }) //already the 16th in Tokyo
+	testCases := []struct {
+		pat   string
+		loc   *time.Location
+		lower string
+		upper string
+	}{
+		{"2017-03-18[Europe/London]", london, "2017-03-18 00:00", "2017-03-19 00:00"},
+		{"2017-03-18@Europe/London", london, "2017-03-18 00:00", "2017-03-19 00:00"},
+		{"2017-03-18[Asia/Tokyo]", tokyo, "2017-03-18 00:00", "2017-03-19 00:00"},
+		{"2017-03-01/2017-03-18[Asia/Tokyo]", tokyo, "2017-03-01 00:00", "2017-03-19 00:00"},
+		{"today@Asia/Tokyo", tokyo, "2017-03-16 00:00", "2017-03-17 00:00"},
+		{"today[Asia/Tokyo]", tokyo, "2017-03-16 00:00", "2017-03-17 00:00"},
+		{"today@Europe/London", london, "2017-03-15 00:00", "2017-03-16 00:00"},
+		{"this_month-1y@Asia/Tokyo", tokyo, "2016-03-01 00:00", "2016-04-01 00:00"},
+		{"2017-03-18T22:50Z[Asia/Tokyo]", time.UTC, "2017-03-18 22:50", "2017-03-18 22:51"}, //the offset wins
+		{"yesterday@Asia/Tokyo..tomorrow@Europe/London", tokyo, "2017-03-15 00:00", "2017-03-17 09:00"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pat, func(t *testing.T) {
+			r, err := p.Expand(tc.pat, time.UTC)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if inc, _ := time.ParseInLocation(format, tc.lower, tc.loc); !inc.Equal(r.LowerInc) {
+				t.Errorf("L %s %s", inc, r.LowerInc)
+			}
+			if exc, _ := time.ParseInLocation(format, tc.upper, tc.loc); !exc.Equal(r.UpperExc) {
+				t.Errorf("U %s %s", exc, r.UpperExc)
+			}
+		})
+	}
+}
+
+func TestBadAnnotation(t *testing.T) {
+	testCases := []struct {
+		pat    string
+		offset int
+		reason error
+	}{
+		{"2017-03-18[Europe/Lundon]", 11, ErrUnknownZone},
+		{"2017-03-18[]", 11, ErrUnknownZone},
+		{"today@", 6, ErrUnknownZone},
+		{"today@Local", 6, ErrUnknownZone},
+		{"2017-03-18Europe/London]", 10, ErrBadSeparator},
+		{"2017-13-18[Europe/London]", 5, ErrMonthOutOfRange},
+		{"today@Asia/Tokyo@Europe/London", 0, ErrNotRecognised}, //only one annotation
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pat, func(t *testing.T) {
+			r, err := Expand(tc.pat, nil)
+			var pe *ParseError
+			if !r.IsZero() || !errors.As(err, &pe) {
+				t.Fatal(r, err)
+			}
+			if pe.Input != tc.pat || pe.Offset != tc.offset || pe.Reason != tc.reason {
+				t.Errorf("%q %d %v", pe.Input, pe.Offset, pe.Reason)
+			}
+		})
+	}
+}